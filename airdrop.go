@@ -0,0 +1,345 @@
+package ordinals
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+)
+
+// AirdropConfig represents configuration for airdropping tokens to many recipients
+type AirdropConfig struct {
+	// Protocol is the token protocol (e.g., TokenTypeBSV21)
+	Protocol TokenType
+	// TokenID is the ID of the token being airdropped
+	TokenID string
+	// Utxos are the UTXOs used to pay for the first batch
+	Utxos []*Utxo
+	// InputTokens are the token UTXOs funding the first batch
+	InputTokens []*TokenUtxo
+	// Recipients is the full list of airdrop recipients
+	Recipients []*TokenDistribution
+	// PaymentPk is the private key for the payment UTXOs
+	PaymentPk *ec.PrivateKey
+	// OrdPk is the private key for the token UTXOs; token change is sent to its address
+	OrdPk *ec.PrivateKey
+	// ChangeAddress is the address to send the payment change of the last batch to
+	// The change of earlier batches funds the next batch, so it always goes to the PaymentPk address.
+	ChangeAddress string
+	// SatsPerKb is the fee rate in satoshis per kilobyte
+	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
+	// Decimals is the number of decimal places for the token
+	Decimals uint8
+	// RecipientsPerTx is the maximum number of recipients paid in one transaction
+	RecipientsPerTx int
+	// MaxBytesPerTx is the maximum size of one transaction in bytes, DEFAULT_MAX_AIRDROP_TX_BYTES if 0
+	// Batches above it pay fewer recipients.
+	MaxBytesPerTx int
+	// Checkpoint resumes a previously planned airdrop from the batch it describes
+	Checkpoint *AirdropCheckpoint
+}
+
+// AirdropCheckpoint captures everything needed to re-plan an airdrop from a given batch
+type AirdropCheckpoint struct {
+	// Batch is the index of the batch this checkpoint starts at
+	Batch int `json:"batch"`
+	// Offset is the index of the first recipient paid by the batch
+	Offset int `json:"offset"`
+	// Utxos are the payment UTXOs funding the batch
+	Utxos []*Utxo `json:"utxos"`
+	// InputTokens are the token UTXOs funding the batch
+	InputTokens []*TokenUtxo `json:"inputTokens"`
+}
+
+// AirdropBatch represents a single signed transaction of an airdrop
+type AirdropBatch struct {
+	// Index is the position of the batch in the airdrop
+	Index int
+	// Tx is the signed transaction for the batch
+	Tx *transaction.Transaction
+	// Recipients are the recipients paid by the batch
+	Recipients []*TokenDistribution
+	// Checkpoint resumes the airdrop at this batch if broadcasting it fails
+	Checkpoint *AirdropCheckpoint
+}
+
+// AirdropPlan represents an ordered set of dependent airdrop transactions
+type AirdropPlan struct {
+	// Batches must be broadcast in order, each spends change from the previous one
+	Batches []*AirdropBatch
+}
+
+// airdropRecipient is the JSON representation of an airdrop recipient
+type airdropRecipient struct {
+	Address      string  `json:"address"`
+	Tokens       float64 `json:"tokens"`
+	OmitMetadata bool    `json:"omitMetadata,omitempty"`
+}
+
+// ParseAirdropCSV reads airdrop recipients from CSV records of the form "address,tokens"
+// A leading header row is skipped if its token column is not a number
+func ParseAirdropCSV(r io.Reader) ([]*TokenDistribution, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipient csv: %w", err)
+	}
+
+	recipients := make([]*TokenDistribution, 0, len(records))
+	for i, record := range records {
+		if len(record) < 2 {
+			return nil, fmt.Errorf("recipient csv line %d: expected address and tokens", i+1)
+		}
+
+		tokens, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			// Allow a header row
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("recipient csv line %d: invalid token amount: %w", i+1, err)
+		}
+
+		recipients = append(recipients, &TokenDistribution{
+			Address: strings.TrimSpace(record[0]),
+			Tokens:  tokens,
+		})
+	}
+
+	return recipients, nil
+}
+
+// ParseAirdropJSON reads airdrop recipients from a JSON array of {"address", "tokens"} objects
+func ParseAirdropJSON(r io.Reader) ([]*TokenDistribution, error) {
+	var entries []airdropRecipient
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode recipient json: %w", err)
+	}
+
+	recipients := make([]*TokenDistribution, 0, len(entries))
+	for _, entry := range entries {
+		recipients = append(recipients, &TokenDistribution{
+			Address:      entry.Address,
+			Tokens:       entry.Tokens,
+			OmitMetadata: entry.OmitMetadata,
+		})
+	}
+
+	return recipients, nil
+}
+
+// PlanAirdrop splits an airdrop into transaction-sized batches and signs each of them
+// Every batch spends the token change and payment change of the batch before it,
+// so the returned transactions must be broadcast in order. If broadcasting fails
+// partway, pass the failed batch's Checkpoint back in the config to re-plan from there.
+func PlanAirdrop(config *AirdropConfig) (*AirdropPlan, error) {
+	if len(config.Recipients) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required")
+	}
+
+	if config.OrdPk == nil {
+		return nil, fmt.Errorf("ordPk is required to receive token change between batches")
+	}
+
+	perTx := config.RecipientsPerTx
+	if perTx <= 0 {
+		perTx = DEFAULT_AIRDROP_RECIPIENTS_PER_TX
+	}
+
+	maxBytes := config.MaxBytesPerTx
+	if maxBytes == 0 {
+		maxBytes = DEFAULT_MAX_AIRDROP_TX_BYTES
+	}
+
+	// Start from the beginning unless resuming from a checkpoint
+	start := config.Checkpoint
+	if start == nil {
+		start = &AirdropCheckpoint{
+			Utxos:       config.Utxos,
+			InputTokens: config.InputTokens,
+		}
+	}
+
+	if start.Offset < 0 || start.Offset >= len(config.Recipients) {
+		return nil, fmt.Errorf("checkpoint offset %d is out of range", start.Offset)
+	}
+
+	// Make sure the token inputs can cover every remaining recipient
+	var required, available uint64
+	for _, recipient := range config.Recipients[start.Offset:] {
+		required += uint64(recipient.Tokens)
+	}
+	for _, tokenUtxo := range start.InputTokens {
+		available += tokenUtxo.Amount
+	}
+	if required > available {
+		return nil, fmt.Errorf("not enough tokens for airdrop: %w", &InsufficientTokensError{
+			Needed:    required,
+			Available: available,
+		})
+	}
+
+	plan := &AirdropPlan{}
+	payUtxos := start.Utxos
+	tokenUtxos := start.InputTokens
+
+	for index, offset := start.Batch, start.Offset; offset < len(config.Recipients); index++ {
+		take := perTx
+		if take > len(config.Recipients)-offset {
+			take = len(config.Recipients) - offset
+		}
+
+		checkpoint := &AirdropCheckpoint{
+			Batch:       index,
+			Offset:      offset,
+			Utxos:       payUtxos,
+			InputTokens: tokenUtxos,
+		}
+
+		// Build the batch, with fewer recipients while it is above the size limit
+		var recipients []*TokenDistribution
+		var tx *transaction.Transaction
+		var end int
+		for {
+			end = offset + take
+			recipients = config.Recipients[offset:end]
+
+			var err error
+			tx, err = TransferOrdTokens(&TransferBsv21TokenConfig{
+				Protocol:      config.Protocol,
+				TokenID:       config.TokenID,
+				Utxos:         payUtxos,
+				InputTokens:   tokenUtxos,
+				Distributions: recipients,
+				PaymentPk:     config.PaymentPk,
+				OrdPk:         config.OrdPk,
+				ChangeAddress: chainedChangeAddress(config.ChangeAddress, end == len(config.Recipients)),
+				SatsPerKb:     config.SatsPerKb,
+				BuildOptions:  config.BuildOptions,
+				Decimals:      config.Decimals,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to build airdrop batch %d: %w", index, err)
+			}
+
+			size := tx.Size()
+			if size <= maxBytes {
+				break
+			}
+			if take == 1 {
+				return nil, fmt.Errorf("airdrop batch %d is %d bytes with a single recipient, above the limit of %d", index, size, maxBytes)
+			}
+
+			// Shrink in proportion to the excess, by at least one recipient
+			smaller := take * maxBytes / size
+			if smaller >= take {
+				smaller = take - 1
+			}
+			if smaller < 1 {
+				smaller = 1
+			}
+			take = smaller
+		}
+
+		plan.Batches = append(plan.Batches, &AirdropBatch{
+			Index:      index,
+			Tx:         tx,
+			Recipients: recipients,
+			Checkpoint: checkpoint,
+		})
+
+		offset = end
+		if offset == len(config.Recipients) {
+			break
+		}
+
+		// Chain token change and payment change into the next batch
+		var err error
+		tokenUtxos, err = airdropTokenChange(tx, config, tokenUtxos, recipients)
+		if err != nil {
+			return nil, fmt.Errorf("airdrop batch %d: %w", index, err)
+		}
+
+		payUtxos = ChangeUtxos(tx)
+		if len(payUtxos) == 0 {
+			return nil, fmt.Errorf("airdrop batch %d left no payment change to fund the next batch", index)
+		}
+	}
+
+	return plan, nil
+}
+
+// airdropTokenChange returns the token change output of an airdrop batch as a token UTXO
+func airdropTokenChange(
+	tx *transaction.Transaction,
+	config *AirdropConfig,
+	inputs []*TokenUtxo,
+	recipients []*TokenDistribution,
+) ([]*TokenUtxo, error) {
+	var totalIn, distributed uint64
+	for _, tokenUtxo := range inputs {
+		totalIn += tokenUtxo.Amount
+	}
+	for _, recipient := range recipients {
+		distributed += uint64(recipient.Tokens)
+	}
+
+	if totalIn <= distributed {
+		return nil, fmt.Errorf("no token change left to fund the next batch")
+	}
+
+	// Find the token change by its script, the transfer to the OrdPk address of the remaining tokens
+	ordAddr, err := script.NewAddressFromPublicKey(config.OrdPk.PubKey(), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token change address: %w", err)
+	}
+	p2pkhScript, err := p2pkh.Lock(ordAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
+	}
+	changeScript, err := (&bsv21.Bsv21{
+		Op:  string(bsv21.OpTransfer),
+		Id:  config.TokenID,
+		Amt: totalIn - distributed,
+	}).Lock(p2pkhScript)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token change script: %w", err)
+	}
+
+	// Token change follows the distributions, so a recipient with the same script is skipped
+	for vout := len(tx.Outputs) - 1; vout >= 0; vout-- {
+		output := tx.Outputs[vout]
+		if !bytes.Equal(*output.LockingScript, *changeScript) {
+			continue
+		}
+
+		return []*TokenUtxo{{
+			Utxo: Utxo{
+				TxID:         tx.TxID().String(),
+				Vout:         uint32(vout),
+				ScriptPubKey: output.LockingScript.String(),
+				Satoshis:     output.Satoshis,
+			},
+			TokenID:  config.TokenID,
+			Protocol: config.Protocol,
+			Amount:   totalIn - distributed,
+			Decimals: config.Decimals,
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("no token change output of %d tokens found", totalIn-distributed)
+}
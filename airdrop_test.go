@@ -0,0 +1,182 @@
+package ordinals
+
+import (
+	"strings"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAirdropRecipients(t *testing.T) {
+	t.Run("CSV with header", func(t *testing.T) {
+		recipients, err := ParseAirdropCSV(strings.NewReader("address,tokens\n1BitcoinEaterAddressDontSendf59kuE,10\n1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa, 25\n"))
+		require.NoError(t, err)
+		require.Len(t, recipients, 2)
+		assert.Equal(t, "1BitcoinEaterAddressDontSendf59kuE", recipients[0].Address)
+		assert.Equal(t, float64(10), recipients[0].Tokens)
+		assert.Equal(t, float64(25), recipients[1].Tokens)
+	})
+
+	t.Run("CSV with bad amount", func(t *testing.T) {
+		_, err := ParseAirdropCSV(strings.NewReader("1BitcoinEaterAddressDontSendf59kuE,10\n1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa,abc\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		recipients, err := ParseAirdropJSON(strings.NewReader(`[{"address":"1BitcoinEaterAddressDontSendf59kuE","tokens":5,"omitMetadata":true}]`))
+		require.NoError(t, err)
+		require.Len(t, recipients, 1)
+		assert.Equal(t, float64(5), recipients[0].Tokens)
+		assert.True(t, recipients[0].OmitMetadata)
+	})
+}
+
+func TestPlanAirdrop(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	paymentAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	require.NoError(t, err)
+
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	recipients := make([]*TokenDistribution, 0, 25)
+	for i := 0; i < 25; i++ {
+		recipients = append(recipients, &TokenDistribution{
+			Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			Tokens:  10,
+		})
+	}
+
+	config := &AirdropConfig{
+		Protocol: TokenTypeBSV21,
+		TokenID:  tokenID,
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     100000,
+		}},
+		InputTokens: []*TokenUtxo{{
+			Utxo: Utxo{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+				Vout:         0,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     1,
+			},
+			TokenID:  tokenID,
+			Protocol: TokenTypeBSV21,
+			Amount:   1000,
+		}},
+		Recipients:      recipients,
+		PaymentPk:       paymentPk,
+		OrdPk:           ordPk,
+		ChangeAddress:   paymentAddr.AddressString,
+		RecipientsPerTx: 10,
+	}
+
+	t.Run("batches chain token and payment change", func(t *testing.T) {
+		plan, err := PlanAirdrop(config)
+		require.NoError(t, err)
+		require.Len(t, plan.Batches, 3)
+
+		assert.Len(t, plan.Batches[0].Recipients, 10)
+		assert.Len(t, plan.Batches[2].Recipients, 5)
+
+		for i := 1; i < len(plan.Batches); i++ {
+			prev := plan.Batches[i-1].Tx
+			tx := plan.Batches[i].Tx
+
+			// Every input of a later batch spends the previous batch
+			for _, input := range tx.Inputs {
+				assert.Equal(t, prev.TxID().String(), input.SourceTXID.String())
+			}
+		}
+
+		// Remaining tokens are carried by the checkpoint of the last batch
+		last := plan.Batches[2].Checkpoint
+		assert.Equal(t, 20, last.Offset)
+		assert.Equal(t, uint64(800), last.InputTokens[0].Amount)
+	})
+
+	t.Run("change address only receives the last change", func(t *testing.T) {
+		otherPk, err := ec.NewPrivateKey()
+		require.NoError(t, err)
+		otherAddr, err := script.NewAddressFromPublicKey(otherPk.PubKey(), true)
+		require.NoError(t, err)
+
+		cfg := *config
+		cfg.ChangeAddress = otherAddr.AddressString
+
+		plan, err := PlanAirdrop(&cfg)
+		require.NoError(t, err)
+		require.Len(t, plan.Batches, 3)
+
+		// Later batches spend the payment change with the payment key
+		verifyInputs(t, plan.Batches[1].Tx)
+		verifyInputs(t, plan.Batches[2].Tx)
+
+		otherScript, err := p2pkh.Lock(otherAddr)
+		require.NoError(t, err)
+		changeUtxos := ChangeUtxos(plan.Batches[2].Tx)
+		require.Len(t, changeUtxos, 1)
+		assert.Equal(t, otherScript.String(), changeUtxos[0].ScriptPubKey)
+	})
+
+	t.Run("resume from checkpoint", func(t *testing.T) {
+		plan, err := PlanAirdrop(config)
+		require.NoError(t, err)
+
+		resumeCfg := *config
+		resumeCfg.Checkpoint = plan.Batches[1].Checkpoint
+
+		resumed, err := PlanAirdrop(&resumeCfg)
+		require.NoError(t, err)
+		require.Len(t, resumed.Batches, 2)
+		assert.Equal(t, 1, resumed.Batches[0].Index)
+		assert.Equal(t, plan.Batches[1].Tx.TxID().String(), resumed.Batches[0].Tx.TxID().String())
+	})
+
+	t.Run("batches stay under the byte limit", func(t *testing.T) {
+		cfg := *config
+		cfg.MaxBytesPerTx = 1000
+
+		plan, err := PlanAirdrop(&cfg)
+		require.NoError(t, err)
+		assert.Greater(t, len(plan.Batches), 3)
+
+		paid := 0
+		for i, batch := range plan.Batches {
+			assert.LessOrEqual(t, batch.Tx.Size(), cfg.MaxBytesPerTx)
+			assert.Equal(t, paid, batch.Checkpoint.Offset)
+			paid += len(batch.Recipients)
+
+			// Each batch spends the token change of the one before it
+			if i > 0 {
+				token := batch.Checkpoint.InputTokens[0]
+				assert.Equal(t, plan.Batches[i-1].Tx.TxID().String(), token.TxID)
+				assert.Equal(t, uint64(1000-10*paid+10*len(batch.Recipients)), token.Amount)
+			}
+		}
+		assert.Equal(t, len(recipients), paid)
+
+		cfg.MaxBytesPerTx = 100
+		_, err = PlanAirdrop(&cfg)
+		assert.ErrorContains(t, err, "single recipient")
+	})
+
+	t.Run("not enough tokens", func(t *testing.T) {
+		cfg := *config
+		cfg.Recipients = append([]*TokenDistribution{{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Tokens: 900}}, recipients...)
+
+		plan, err := PlanAirdrop(&cfg)
+		assert.Error(t, err)
+		assert.Nil(t, plan)
+	})
+}
@@ -0,0 +1,134 @@
+package ordinals
+
+import (
+	"fmt"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// BatchInscribeConfig represents configuration for inscribing more destinations than fit in one transaction
+type BatchInscribeConfig struct {
+	// Utxos are the UTXOs used to pay for the first batch, later batches are funded by its change
+	Utxos []*Utxo
+	// Destinations are all inscriptions to create, in order
+	Destinations []*Destination
+	// PaymentPk is the private key for the payment UTXOs
+	PaymentPk *ec.PrivateKey
+	// ChangeAddress is the address to send the change of the last batch to
+	// The change of earlier batches funds the next batch, so it always goes to the PaymentPk address.
+	ChangeAddress string
+	// SatsPerKb is the fee rate in satoshis per kilobyte
+	SatsPerKb uint64
+	// BuildOptions configures how the transactions are built, e.g. a custom fee model
+	BuildOptions
+	// MaxOutputs is the maximum number of inscriptions in one transaction, DEFAULT_BATCH_MAX_OUTPUTS if 0
+	MaxOutputs int
+	// MaxBytes is the maximum total size of the inscription scripts in one transaction, DEFAULT_BATCH_MAX_BYTES if 0
+	// A single inscription larger than this gets a transaction of its own.
+	MaxBytes int
+	// Progress is called after each batch is built
+	Progress func(progress *BatchInscribeProgress)
+}
+
+// BatchInscribeProgress reports a built batch
+type BatchInscribeProgress struct {
+	// Batch is the index of the batch
+	Batch int
+	// Batches is the total number of batches
+	Batches int
+	// Destinations are the destinations inscribed by the batch
+	Destinations []*Destination
+	// Inscribed is the number of destinations inscribed so far, including this batch
+	Inscribed int
+	// Total is the number of destinations to inscribe
+	Total int
+	// Tx is the signed transaction of the batch
+	Tx *transaction.Transaction
+}
+
+// BatchInscribe splits destinations into transaction-sized batches and inscribes them
+// Batches are limited by MaxOutputs and MaxBytes. Each batch is funded by the change of the
+// one before, so the transactions must be broadcast in the order they are returned.
+func BatchInscribe(config *BatchInscribeConfig) (*ChainResult, error) {
+	batches, err := splitDestinations(config.Destinations, config.MaxOutputs, config.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build one chain step per batch
+	steps := make([]ChainStep, len(batches))
+	inscribed := 0
+	for i, batch := range batches {
+		steps[i] = func(utxos []*Utxo) (*transaction.Transaction, error) {
+			tx, err := CreateOrdinals(&CreateOrdinalsConfig{
+				Utxos:         utxos,
+				Destinations:  batch,
+				PaymentPk:     config.PaymentPk,
+				ChangeAddress: chainedChangeAddress(config.ChangeAddress, i == len(batches)-1),
+				SatsPerKb:     config.SatsPerKb,
+				BuildOptions:  config.BuildOptions,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			inscribed += len(batch)
+			if config.Progress != nil {
+				config.Progress(&BatchInscribeProgress{
+					Batch:        i,
+					Batches:      len(batches),
+					Destinations: batch,
+					Inscribed:    inscribed,
+					Total:        len(config.Destinations),
+					Tx:           tx,
+				})
+			}
+
+			return tx, nil
+		}
+	}
+
+	return Chain(config.Utxos, steps...)
+}
+
+// splitDestinations groups destinations in order into batches of at most maxOutputs destinations
+// and maxBytes of inscription scripts
+func splitDestinations(destinations []*Destination, maxOutputs, maxBytes int) ([][]*Destination, error) {
+	if maxOutputs <= 0 {
+		maxOutputs = DEFAULT_BATCH_MAX_OUTPUTS
+	}
+	if maxBytes <= 0 {
+		maxBytes = DEFAULT_BATCH_MAX_BYTES
+	}
+
+	var batches [][]*Destination
+	var batch []*Destination
+	batchBytes := 0
+	for i, destination := range destinations {
+		if destination == nil || destination.Inscription == nil {
+			return nil, fmt.Errorf("inscription is required for all destinations, destination %d has none", i)
+		}
+
+		lockingScript, err := destinationScript(destination)
+		if err != nil {
+			return nil, fmt.Errorf("destination %d: %w", i, err)
+		}
+		size := len(*lockingScript)
+
+		// Start a new batch when this destination doesn't fit
+		if len(batch) > 0 && (len(batch) == maxOutputs || batchBytes+size > maxBytes) {
+			batches = append(batches, batch)
+			batch, batchBytes = nil, 0
+		}
+
+		batch = append(batch, destination)
+		batchBytes += size
+	}
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
@@ -0,0 +1,152 @@
+package ordinals
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBatchInscribe tests splitting inscriptions into chained batches
+func TestBatchInscribe(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	payAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	require.NoError(t, err)
+
+	// destinations creates count text inscriptions of size bytes each
+	destinations := func(count, size int) []*Destination {
+		dests := make([]*Destination, count)
+		for i := range dests {
+			content := append([]byte(fmt.Sprintf("%05d", i)), bytes.Repeat([]byte("x"), size)...)
+			dests[i] = &Destination{
+				Address:     payAddr.AddressString,
+				Inscription: &inscription.Inscription{File: inscription.File{Content: content, Type: "text/plain"}},
+			}
+		}
+		return dests
+	}
+
+	t.Run("split by output count", func(t *testing.T) {
+		var progress []*BatchInscribeProgress
+		result, err := BatchInscribe(&BatchInscribeConfig{
+			Utxos:        walletUtxos(t, payAddr, 1, 1000000),
+			Destinations: destinations(250, 10),
+			PaymentPk:    paymentPk,
+			Progress: func(p *BatchInscribeProgress) {
+				progress = append(progress, p)
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Transactions, 3)
+
+		// 100 + 1 change, 100 + 1 change, 50 + 1 change
+		assert.Len(t, result.Transactions[0].Outputs, 101)
+		assert.Len(t, result.Transactions[1].Outputs, 101)
+		assert.Len(t, result.Transactions[2].Outputs, 51)
+
+		// Each batch is funded by the change of the one before
+		for i := 1; i < len(result.Transactions); i++ {
+			assert.Equal(t, result.Transactions[i-1].TxID().String(), result.Transactions[i].Inputs[0].SourceTXID.String())
+		}
+
+		require.Len(t, progress, 3)
+		for i, p := range progress {
+			assert.Equal(t, i, p.Batch)
+			assert.Equal(t, 3, p.Batches)
+			assert.Equal(t, 250, p.Total)
+			assert.Equal(t, result.Transactions[i], p.Tx)
+		}
+		assert.Equal(t, 100, progress[0].Inscribed)
+		assert.Equal(t, 250, progress[2].Inscribed)
+		assert.Len(t, progress[2].Destinations, 50)
+	})
+
+	t.Run("change address only receives the last change", func(t *testing.T) {
+		otherPk, err := ec.NewPrivateKey()
+		require.NoError(t, err)
+		otherAddr, err := script.NewAddressFromPublicKey(otherPk.PubKey(), true)
+		require.NoError(t, err)
+
+		result, err := BatchInscribe(&BatchInscribeConfig{
+			Utxos:         walletUtxos(t, payAddr, 1, 1000000),
+			Destinations:  destinations(150, 10),
+			PaymentPk:     paymentPk,
+			ChangeAddress: otherAddr.AddressString,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Transactions, 2)
+
+		// Batch 2 spends the change of batch 1 with the payment key
+		verifyInputs(t, result.Transactions[1])
+
+		otherScript, err := p2pkh.Lock(otherAddr)
+		require.NoError(t, err)
+		require.Len(t, result.Utxos, 1)
+		assert.Equal(t, otherScript.String(), result.Utxos[0].ScriptPubKey)
+	})
+
+	t.Run("split by size", func(t *testing.T) {
+		batches, err := splitDestinations(destinations(10, 1000), 0, 3500)
+		require.NoError(t, err)
+		require.Len(t, batches, 4)
+		assert.Len(t, batches[0], 3)
+		assert.Len(t, batches[3], 1)
+
+		// An inscription larger than the limit gets a batch of its own
+		batches, err = splitDestinations(destinations(2, 5000), 0, 3500)
+		require.NoError(t, err)
+		assert.Len(t, batches, 2)
+	})
+
+	t.Run("missing inscription", func(t *testing.T) {
+		dests := destinations(3, 10)
+		dests[1].Inscription = nil
+
+		_, err := BatchInscribe(&BatchInscribeConfig{
+			Utxos:        walletUtxos(t, payAddr, 1, 1000000),
+			Destinations: dests,
+			PaymentPk:    paymentPk,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "destination 1")
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		_, err := BatchInscribe(&BatchInscribeConfig{
+			Utxos:        walletUtxos(t, payAddr, 1, 150),
+			Destinations: destinations(250, 10),
+			PaymentPk:    paymentPk,
+		})
+		require.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+}
+
+// verifyInputs runs the script interpreter on every input of a signed transaction
+func verifyInputs(t *testing.T, tx *transaction.Transaction) {
+	t.Helper()
+
+	for i := range tx.Inputs {
+		verifyInput(t, tx, i)
+	}
+}
+
+// verifyInput runs the script interpreter on one input of a signed transaction
+func verifyInput(t *testing.T, tx *transaction.Transaction, i int) {
+	t.Helper()
+
+	err := interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, i, tx.Inputs[i].SourceTxOutput()),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	)
+	require.NoError(t, err, "input %d", i)
+}
@@ -0,0 +1,271 @@
+package ordinals
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// Key prefixes used by TokenValidator in its KVStore
+const (
+	bsv21TxKey    = "bsv21:tx:"
+	bsv21TxoKey   = "bsv21:txo:"
+	bsv21TokenKey = "bsv21:token:"
+)
+
+// TokenValidator tracks BSV21 token state from transactions, without the GorillaPool API
+// Transactions must be added in the order they were mined, parents before children.
+// A deploy+mint creates a token with the outpoint of its output as ID. Transfers and burns are
+// valid when the valid, unspent token inputs of the same ID cover all outputs of that ID,
+// otherwise every output of that ID is invalid. Input tokens left over are burned.
+type TokenValidator struct {
+	mu    sync.Mutex
+	store KVStore
+}
+
+// TokenInfo describes a deployed BSV21 token
+type TokenInfo struct {
+	// ID is the outpoint of the deploy+mint output
+	ID string `json:"id"`
+	// Symbol is the token symbol
+	Symbol string `json:"sym,omitempty"`
+	// Decimals is the number of decimal places
+	Decimals uint8 `json:"dec"`
+	// Icon is the icon outpoint
+	Icon string `json:"icon,omitempty"`
+	// Supply is the raw amount minted
+	Supply uint64 `json:"supply"`
+}
+
+// TokenOutput is the indexed state of a BSV21 output
+type TokenOutput struct {
+	// TokenID is the ID of the token
+	TokenID string `json:"id"`
+	// Op is the operation of the output: deploy+mint, transfer or burn
+	Op string `json:"op"`
+	// Amount is the raw token amount written in the output
+	Amount uint64 `json:"amt"`
+	// Valid is true if the inputs covered the output
+	Valid bool `json:"valid"`
+	// Spent is true once a later transaction spends the output
+	Spent bool `json:"spent"`
+}
+
+// Holds returns the raw amount of tokens the output can spend
+// Invalid, spent and burn outputs hold no tokens.
+func (o *TokenOutput) Holds() uint64 {
+	if !o.Valid || o.Spent || o.Op == string(bsv21.OpBurn) {
+		return 0
+	}
+
+	return o.Amount
+}
+
+// NewTokenValidator creates a TokenValidator persisting its state to store
+func NewTokenValidator(store KVStore) *TokenValidator {
+	return &TokenValidator{store: store}
+}
+
+// AddTransaction applies the BSV21 operations of a transaction
+// Adding a transaction twice has no effect.
+func (v *TokenValidator) AddTransaction(tx *transaction.Transaction) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	txid := tx.TxID().String()
+	done, err := v.store.Get(bsv21TxKey + txid)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction state: %w", err)
+	}
+	if done != nil {
+		return nil
+	}
+
+	// Stage all changes, so the transaction is saved as one write
+	batch := newKVBatch(v.store)
+
+	// Spend the token inputs, only valid ones bring tokens
+	tokensIn := make(map[string]uint64)
+	for _, input := range tx.Inputs {
+		outpoint := fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)
+		output, err := v.tokenOutput(outpoint)
+		if err != nil {
+			return err
+		}
+		if output == nil || output.Spent {
+			continue
+		}
+
+		tokensIn[output.TokenID] += output.Holds()
+		output.Spent = true
+		if err = putJSON(batch, bsv21TxoKey+outpoint, output); err != nil {
+			return err
+		}
+	}
+
+	// Collect the token outputs and the total sent of each token
+	outputs := make(map[uint32]*TokenOutput)
+	tokensOut := make(map[string]uint64)
+	for vout, txOutput := range tx.Outputs {
+		parsed := ParseOutput(txid, uint32(vout), txOutput)
+		if parsed.Token == nil || parsed.Token.ID == "" {
+			continue
+		}
+		token := parsed.Token
+
+		output := &TokenOutput{TokenID: token.ID, Op: token.Op, Amount: token.Amount}
+		switch token.Op {
+		case string(bsv21.OpMint):
+			// A mint is valid on its own and creates the token
+			output.Valid = token.Amount > 0
+			if output.Valid {
+				err = putJSON(batch, bsv21TokenKey+token.ID, &TokenInfo{
+					ID:       token.ID,
+					Symbol:   token.Symbol,
+					Decimals: token.Decimals,
+					Icon:     token.Icon,
+					Supply:   token.Amount,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		case string(bsv21.OpTransfer), string(bsv21.OpBurn):
+			tokensOut[token.ID] += token.Amount
+		default:
+			continue
+		}
+		outputs[uint32(vout)] = output
+	}
+
+	// Transfers and burns of a token are valid together when the inputs cover them
+	for vout, output := range outputs {
+		if output.Op != string(bsv21.OpMint) {
+			output.Valid = tokensIn[output.TokenID] >= tokensOut[output.TokenID]
+		}
+		if err = putJSON(batch, fmt.Sprintf("%s%s_%d", bsv21TxoKey, txid, vout), output); err != nil {
+			return err
+		}
+	}
+
+	if err = batch.Set(bsv21TxKey+txid, []byte{1}); err != nil {
+		return fmt.Errorf("failed to save transaction state: %w", err)
+	}
+
+	if err = batch.Commit(); err != nil {
+		return fmt.Errorf("failed to save token state: %w", err)
+	}
+
+	return nil
+}
+
+// TokenOutput returns the indexed state of an output, or nil if it isn't a BSV21 output
+func (v *TokenValidator) TokenOutput(txid string, vout uint32) (*TokenOutput, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.tokenOutput(fmt.Sprintf("%s_%d", txid, vout))
+}
+
+// ValidateTokenUtxo checks that a token UTXO is a valid, unspent output of its token
+// It returns the raw amount the output holds, and ErrInvalidToken if it holds no tokens
+// or its TokenID or Amount don't match the indexed state.
+func (v *TokenValidator) ValidateTokenUtxo(utxo *TokenUtxo) (uint64, error) {
+	output, err := v.TokenOutput(utxo.TxID, utxo.Vout)
+	if err != nil {
+		return 0, err
+	}
+
+	outpoint := fmt.Sprintf("%s_%d", utxo.TxID, utxo.Vout)
+	switch {
+	case output == nil:
+		return 0, fmt.Errorf("%w: %s is not a known token output", ErrInvalidToken, outpoint)
+	case !output.Valid:
+		return 0, fmt.Errorf("%w: %s is an invalid %s", ErrInvalidToken, outpoint, output.Op)
+	case output.Spent:
+		return 0, fmt.Errorf("%w: %s is spent", ErrInvalidToken, outpoint)
+	case output.Holds() == 0:
+		return 0, fmt.Errorf("%w: %s holds no tokens", ErrInvalidToken, outpoint)
+	case output.TokenID != utxo.TokenID:
+		return output.Holds(), fmt.Errorf("%w: %s holds token %s, not %s", ErrInvalidToken, outpoint, output.TokenID, utxo.TokenID)
+	case output.Amount != utxo.Amount:
+		return output.Holds(), fmt.Errorf("%w: %s holds %d tokens, not %d", ErrInvalidToken, outpoint, output.Amount, utxo.Amount)
+	}
+
+	return output.Holds(), nil
+}
+
+// Token returns a deployed token, or nil if it isn't known
+func (v *TokenValidator) Token(id string) (*TokenInfo, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	value, err := v.store.Get(bsv21TokenKey + id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	info := &TokenInfo{}
+	if err = json.Unmarshal(value, info); err != nil {
+		return nil, fmt.Errorf("failed to decode token: %w", err)
+	}
+
+	return info, nil
+}
+
+// Tokens returns the IDs of every deployed token
+func (v *TokenValidator) Tokens() ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	keys, err := v.store.Keys(bsv21TokenKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, strings.TrimPrefix(key, bsv21TokenKey))
+	}
+
+	return ids, nil
+}
+
+// tokenOutput reads the state of an output, or nil if it isn't indexed
+func (v *TokenValidator) tokenOutput(outpoint string) (*TokenOutput, error) {
+	value, err := v.store.Get(bsv21TxoKey + outpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token output: %w", err)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	output := &TokenOutput{}
+	if err = json.Unmarshal(value, output); err != nil {
+		return nil, fmt.Errorf("failed to decode token output: %w", err)
+	}
+
+	return output, nil
+}
+
+// putJSON stores a value as JSON
+func putJSON(store KVStore, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	if err = store.Set(key, data); err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package ordinals
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenValidator tests tracking BSV21 state from deploy, transfer and burn transactions
+func TestTokenValidator(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+	require.NoError(t, err)
+
+	validator := NewTokenValidator(NewMemoryStore())
+
+	// Deploy a token
+	deployTx, err := DeployBsv21Token(&DeployBsv21TokenConfig{
+		Symbol: "TEST",
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     100000,
+		}},
+		InitialDistribution: &TokenDistribution{Address: ordAddr.AddressString, Tokens: 1000},
+		PaymentPk:           paymentPk,
+		DestinationAddress:  ordAddr.AddressString,
+	})
+	require.NoError(t, err)
+	require.NoError(t, validator.AddTransaction(deployTx))
+
+	tokenID := deployTx.TxID().String() + "_0"
+	mint := &TokenUtxo{
+		Utxo:     utxoAt(deployTx, 0),
+		TokenID:  tokenID,
+		Protocol: TokenTypeBSV21,
+		Amount:   1000,
+	}
+
+	t.Run("deploy", func(t *testing.T) {
+		info, err := validator.Token(tokenID)
+		require.NoError(t, err)
+		assert.Equal(t, &TokenInfo{ID: tokenID, Symbol: "TEST", Supply: 1000}, info)
+
+		ids, err := validator.Tokens()
+		require.NoError(t, err)
+		assert.Equal(t, []string{tokenID}, ids)
+
+		amount, err := validator.ValidateTokenUtxo(mint)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1000), amount)
+	})
+
+	// transfer sends 400 tokens and burns burnTokens of the inputs
+	transfer := func(t *testing.T, inputs []*TokenUtxo, payment Utxo, burnTokens uint64) *transaction.Transaction {
+		tx, err := TransferOrdTokens(&TransferBsv21TokenConfig{
+			Protocol:      TokenTypeBSV21,
+			TokenID:       tokenID,
+			Utxos:         []*Utxo{&payment},
+			InputTokens:   inputs,
+			Distributions: []*TokenDistribution{{Address: ordAddr.AddressString, Tokens: 400}},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+			BurnTokens:    burnTokens,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	transferTx := transfer(t, []*TokenUtxo{mint}, utxoAt(deployTx, uint32(len(deployTx.Outputs)-1)), 100)
+	require.NoError(t, validator.AddTransaction(transferTx))
+	transferTxID := transferTx.TxID().String()
+
+	t.Run("transfer and burn", func(t *testing.T) {
+		_, err := validator.ValidateTokenUtxo(mint)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		sent, err := validator.TokenOutput(transferTxID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, &TokenOutput{TokenID: tokenID, Op: "transfer", Amount: 400, Valid: true}, sent)
+
+		// The rest is split between token change and the burn
+		var held, burned uint64
+		for vout := range transferTx.Outputs {
+			output, err := validator.TokenOutput(transferTxID, uint32(vout))
+			require.NoError(t, err)
+			if output == nil {
+				continue
+			}
+			assert.True(t, output.Valid)
+			held += output.Holds()
+			if output.Op == "burn" {
+				burned += output.Amount
+			}
+		}
+		assert.Equal(t, uint64(900), held)
+		assert.Equal(t, uint64(100), burned)
+
+		// A wrong amount is reported with the amount the output holds
+		amount, err := validator.ValidateTokenUtxo(&TokenUtxo{Utxo: utxoAt(transferTx, 0), TokenID: tokenID, Amount: 500})
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		assert.Equal(t, uint64(400), amount)
+
+		// Adding a transaction again changes nothing
+		require.NoError(t, validator.AddTransaction(transferTx))
+		sent, err = validator.TokenOutput(transferTxID, 0)
+		require.NoError(t, err)
+		assert.False(t, sent.Spent)
+	})
+
+	t.Run("listing, purchase and cancel", func(t *testing.T) {
+		// The token change of the transfer holds the other 500 tokens
+		var change *TokenUtxo
+		for vout, output := range transferTx.Outputs {
+			if token := decodeBsv21(output.LockingScript); token != nil && token.Op == "transfer" && token.Amt == 500 {
+				change = &TokenUtxo{Utxo: utxoAt(transferTx, uint32(vout)), TokenID: tokenID, Protocol: TokenTypeBSV21, Amount: 500}
+			}
+		}
+		require.NotNil(t, change)
+
+		// list lists a token UTXO and returns the listing
+		payments := ChangeUtxos(transferTx)
+		list := func(t *testing.T, tokenUtxo *TokenUtxo) *TokenUtxo {
+			listTx, err := CreateOrdTokenListings(&CreateOrdTokenListingsConfig{
+				Utxos: payments,
+				Listings: []*struct {
+					PayAddress  string
+					Price       uint64
+					ListingUtxo *TokenUtxo
+					OrdAddress  string
+				}{{PayAddress: ordAddr.AddressString, Price: 1000, ListingUtxo: tokenUtxo, OrdAddress: ordAddr.AddressString}},
+				PaymentPk: paymentPk,
+				OrdPk:     ordPk,
+			})
+			require.NoError(t, err)
+			require.NoError(t, validator.AddTransaction(listTx))
+			payments = ChangeUtxos(listTx)
+
+			// The listing holds the tokens
+			listed, err := validator.TokenOutput(listTx.TxID().String(), 0)
+			require.NoError(t, err)
+			require.NotNil(t, listed)
+			assert.True(t, listed.Valid)
+			assert.Equal(t, tokenUtxo.Amount, listed.Holds())
+
+			return &TokenUtxo{Utxo: utxoAt(listTx, 0), TokenID: tokenID, Protocol: TokenTypeBSV21, Amount: tokenUtxo.Amount}
+		}
+
+		listing := list(t, change)
+		purchaseTx, err := PurchaseOrdTokenListing(&PurchaseOrdTokenListingConfig{
+			Protocol:    TokenTypeBSV21,
+			TokenID:     tokenID,
+			Utxos:       payments,
+			PaymentPk:   paymentPk,
+			ListingUtxo: listing,
+			OrdAddress:  ordAddr.AddressString,
+		})
+		require.NoError(t, err)
+		require.NoError(t, validator.AddTransaction(purchaseTx))
+		payments = ChangeUtxos(purchaseTx)
+
+		purchased, err := validator.TokenOutput(purchaseTx.TxID().String(), 0)
+		require.NoError(t, err)
+		require.NotNil(t, purchased)
+		assert.True(t, purchased.Valid)
+		assert.Equal(t, uint64(500), purchased.Holds())
+
+		listing = list(t, &TokenUtxo{Utxo: utxoAt(purchaseTx, 0), TokenID: tokenID, Protocol: TokenTypeBSV21, Amount: 500})
+		cancelTx, err := CancelOrdTokenListings(&CancelOrdTokenListingsConfig{
+			Utxos:        payments,
+			ListingUtxos: []*TokenUtxo{listing},
+			OrdPk:        ordPk,
+			PaymentPk:    paymentPk,
+		})
+		require.NoError(t, err)
+		require.NoError(t, validator.AddTransaction(cancelTx))
+
+		returned, err := validator.TokenOutput(cancelTx.TxID().String(), 0)
+		require.NoError(t, err)
+		require.NotNil(t, returned)
+		assert.True(t, returned.Valid)
+		assert.Equal(t, uint64(500), returned.Holds())
+	})
+
+	t.Run("outputs above inputs are invalid", func(t *testing.T) {
+		// Claim the 400 token output holds 1000 tokens, the transfer sends 1000 from 400
+		inflated := &TokenUtxo{Utxo: utxoAt(transferTx, 0), TokenID: tokenID, Protocol: TokenTypeBSV21, Amount: 1000}
+		inflatedTx := transfer(t, []*TokenUtxo{inflated}, utxoAt(transferTx, uint32(len(transferTx.Outputs)-1)), 0)
+		require.NoError(t, validator.AddTransaction(inflatedTx))
+
+		for vout := range inflatedTx.Outputs {
+			output, err := validator.TokenOutput(inflatedTx.TxID().String(), uint32(vout))
+			require.NoError(t, err)
+			if output != nil {
+				assert.False(t, output.Valid)
+				assert.Zero(t, output.Holds())
+			}
+		}
+
+		_, err := validator.ValidateTokenUtxo(&TokenUtxo{Utxo: utxoAt(inflatedTx, 0), TokenID: tokenID, Amount: 400})
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		output, err := validator.TokenOutput(deployTx.TxID().String(), 1)
+		require.NoError(t, err)
+		assert.Nil(t, output)
+
+		_, err = validator.ValidateTokenUtxo(&TokenUtxo{Utxo: utxoAt(deployTx, 1), TokenID: tokenID})
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+// utxoAt returns an output of a transaction as a UTXO
+func utxoAt(tx *transaction.Transaction, vout uint32) Utxo {
+	return Utxo{
+		TxID:         tx.TxID().String(),
+		Vout:         vout,
+		ScriptPubKey: tx.Outputs[vout].LockingScript.String(),
+		Satoshis:     tx.Outputs[vout].Satoshis,
+	}
+}
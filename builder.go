@@ -0,0 +1,262 @@
+package ordinals
+
+import (
+	"fmt"
+	"log/slog"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/bsv-blockchain/go-sdk/util"
+)
+
+// TxRole describes what a transaction input or output is used for
+type TxRole string
+
+const (
+	// TxRolePayment is a funding input or a payment output to an address
+	TxRolePayment TxRole = "payment"
+	// TxRoleOrdinal is a 1 sat ordinal being spent or sent
+	TxRoleOrdinal TxRole = "ordinal"
+	// TxRoleInscription is a newly inscribed ordinal output
+	TxRoleInscription TxRole = "inscription"
+	// TxRoleToken is a token input or a token output sent to a recipient
+	TxRoleToken TxRole = "token"
+	// TxRoleTokenChange is a token output returned to the sender
+	TxRoleTokenChange TxRole = "token-change"
+	// TxRoleTokenBurn is a token output recording a burn
+	TxRoleTokenBurn TxRole = "token-burn"
+	// TxRoleListing is a marketplace listing being created, purchased or cancelled
+	TxRoleListing TxRole = "listing"
+	// TxRoleOpReturn is an unspendable data output
+	TxRoleOpReturn TxRole = "op-return"
+	// TxRoleChange is a payment change output
+	TxRoleChange TxRole = "change"
+)
+
+// Builder assembles a transaction while recording the role of each input and output
+// Every builder function in this package is composed from its methods, use NewBuilder to
+// combine several operations in one transaction.
+// In a dry run inputs are left unsigned so a TxPlan can be produced without private keys.
+type Builder struct {
+	tx          *transaction.Transaction
+	dryRun      bool
+	inputRoles  map[*transaction.TransactionInput]TxRole
+	outputRoles map[*transaction.TransactionOutput]TxRole
+	outpoints   map[string]TxRole
+	options     BuildOptions
+}
+
+// NewBuilder creates a Builder for a custom transaction
+// Add inputs and outputs, then call Change, Fee and Sign in that order.
+func NewBuilder(options BuildOptions) *Builder {
+	b := newTxBuilder(false)
+	b.options = options
+	return b
+}
+
+// newTxBuilder creates a builder for a new transaction
+func newTxBuilder(dryRun bool) *Builder {
+	return &Builder{
+		tx:          transaction.NewTransaction(),
+		dryRun:      dryRun,
+		inputRoles:  make(map[*transaction.TransactionInput]TxRole),
+		outputRoles: make(map[*transaction.TransactionOutput]TxRole),
+		outpoints:   make(map[string]TxRole),
+	}
+}
+
+// unlocker returns the P2PKH unlocking template for an input owned by pk
+// In a dry run the key is not needed and the input is left unsigned
+func (b *Builder) unlocker(pk *ec.PrivateKey) (transaction.UnlockingScriptTemplate, error) {
+	if b.dryRun {
+		return &unsignedP2PKH{}, nil
+	}
+
+	return p2pkh.Unlock(pk, nil)
+}
+
+// addInput adds a UTXO as an input with the given role
+// An outpoint can only be spent once, and payment inputs must pass the PaymentScreen of the options.
+func (b *Builder) addInput(utxo *Utxo, unlocker transaction.UnlockingScriptTemplate, role TxRole) error {
+	// Reject outpoints that are already spent by this transaction
+	outpoint := fmt.Sprintf("%s_%d", utxo.TxID, utxo.Vout)
+	if spentAs, ok := b.outpoints[outpoint]; ok {
+		return fmt.Errorf("%w: %s is already spent as %s input", ErrDuplicateOutpoint, outpoint, spentAs)
+	}
+
+	// Payments are plain satoshis, spending an ordinal as one would destroy it
+	if role == TxRolePayment {
+		if reason := b.options.PaymentScreen.check(utxo); reason != "" {
+			b.log().Debug("rejected payment input", "outpoint", outpoint, "reason", reason)
+			return fmt.Errorf("%w: %s %s", ErrOrdinalPayment, outpoint, reason)
+		}
+	}
+
+	err := b.tx.AddInputFrom(
+		utxo.TxID,
+		utxo.Vout,
+		utxo.ScriptPubKey,
+		utxo.Satoshis,
+		unlocker,
+	)
+	if err != nil {
+		return err
+	}
+
+	b.inputRoles[b.tx.Inputs[len(b.tx.Inputs)-1]] = role
+	b.outpoints[outpoint] = role
+	b.log().Debug("added input", "index", len(b.tx.Inputs)-1, "outpoint", outpoint, "satoshis", utxo.Satoshis, "role", role)
+	return nil
+}
+
+// addOutput adds an output with the given role
+func (b *Builder) addOutput(output *transaction.TransactionOutput, role TxRole) {
+	b.tx.AddOutput(output)
+	b.outputRoles[output] = role
+	b.log().Debug("added output", "index", len(b.tx.Outputs)-1, "satoshis", output.Satoshis, "role", role)
+}
+
+// log returns the logger of the build options
+func (b *Builder) log() *slog.Logger {
+	return loggerOrDiscard(b.options.Logger)
+}
+
+// Transaction returns the transaction being built
+func (b *Builder) Transaction() *transaction.Transaction {
+	return b.tx
+}
+
+// Sign signs every input and returns the transaction
+// In a dry run the inputs are left unsigned.
+func (b *Builder) Sign() (*transaction.Transaction, error) {
+	if b.dryRun {
+		return b.tx, nil
+	}
+
+	err := b.tx.Sign()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.tx, nil
+}
+
+// placeholderScript returns a P2PKH script used in place of an address derived from a missing key
+// It has the same size as any other P2PKH script so fee estimates are unaffected
+func placeholderScript() *script.Script {
+	lockingScript, _ := p2pkh.Lock(&script.Address{PublicKeyHash: make([]byte, 20)})
+	return lockingScript
+}
+
+// TxPlan describes a transaction without signing it
+// It is produced by the Estimate functions using the same code path as the real builders.
+type TxPlan struct {
+	// Tx is the unsigned transaction with fee and change applied
+	Tx *transaction.Transaction
+	// Inputs are the planned inputs in order
+	Inputs []*PlannedInput
+	// Outputs are the planned outputs in order
+	Outputs []*PlannedOutput
+	// Size is the estimated size of the signed transaction in bytes
+	Size uint64
+	// Fee is the fee paid by the transaction in satoshis
+	Fee uint64
+	// Change is the total of the payment change outputs in satoshis
+	Change uint64
+}
+
+// PlannedInput describes an input of a TxPlan
+type PlannedInput struct {
+	// TxID is the transaction ID of the UTXO being spent
+	TxID string
+	// Vout is the output index of the UTXO being spent
+	Vout uint32
+	// Satoshis is the value of the UTXO being spent
+	Satoshis uint64
+	// Role is what the input is used for
+	Role TxRole
+}
+
+// PlannedOutput describes an output of a TxPlan
+type PlannedOutput struct {
+	// Vout is the output index
+	Vout uint32
+	// Satoshis is the value of the output
+	Satoshis uint64
+	// LockingScript is the locking script of the output
+	LockingScript *script.Script
+	// Role is what the output is used for
+	Role TxRole
+}
+
+// Plan describes the transaction built so far
+func (b *Builder) Plan() *TxPlan {
+	plan := &TxPlan{
+		Tx:   b.tx,
+		Size: estimateTxSize(b.tx),
+	}
+
+	var totalIn, totalOut uint64
+	for _, input := range b.tx.Inputs {
+		var sats uint64
+		if sourceSats := input.SourceTxSatoshis(); sourceSats != nil {
+			sats = *sourceSats
+		}
+		totalIn += sats
+
+		plan.Inputs = append(plan.Inputs, &PlannedInput{
+			TxID:     input.SourceTXID.String(),
+			Vout:     input.SourceTxOutIndex,
+			Satoshis: sats,
+			Role:     b.inputRoles[input],
+		})
+	}
+
+	for vout, output := range b.tx.Outputs {
+		totalOut += output.Satoshis
+		if output.Change {
+			plan.Change += output.Satoshis
+		}
+
+		plan.Outputs = append(plan.Outputs, &PlannedOutput{
+			Vout:          uint32(vout),
+			Satoshis:      output.Satoshis,
+			LockingScript: output.LockingScript,
+			Role:          b.outputRoles[output],
+		})
+	}
+
+	if totalIn > totalOut {
+		plan.Fee = totalIn - totalOut
+	}
+
+	return plan
+}
+
+// estimateTxSize returns the size of a transaction once signed
+// Unsigned inputs are counted using the estimated length of their unlocking script
+func estimateTxSize(tx *transaction.Transaction) uint64 {
+	size := 4
+	size += util.VarInt(len(tx.Inputs)).Length()
+	for vin, input := range tx.Inputs {
+		size += 40
+		scriptLen := 0
+		if input.UnlockingScript != nil && len(*input.UnlockingScript) > 0 {
+			scriptLen = len(*input.UnlockingScript)
+		} else if input.UnlockingScriptTemplate != nil {
+			scriptLen = int(input.UnlockingScriptTemplate.EstimateLength(tx, uint32(vin)))
+		}
+		size += util.VarInt(scriptLen).Length() + scriptLen
+	}
+	size += util.VarInt(len(tx.Outputs)).Length()
+	for _, output := range tx.Outputs {
+		size += 8
+		size += util.VarInt(len(*output.LockingScript)).Length()
+		size += len(*output.LockingScript)
+	}
+	size += 4
+
+	return uint64(size)
+}
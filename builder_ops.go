@@ -0,0 +1,337 @@
+package ordinals
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
+	"github.com/bitcoin-sv/go-templates/template/ordp2pkh"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+)
+
+// AddPaymentUtxos adds funding inputs owned by pk
+func (b *Builder) AddPaymentUtxos(utxos []*Utxo, pk *ec.PrivateKey) error {
+	for _, utxo := range utxos {
+		unlocker, err := b.unlocker(pk)
+		if err != nil {
+			return fmt.Errorf("private key is required to sign the payment: %w", err)
+		}
+
+		err = b.addInput(utxo, unlocker, TxRolePayment)
+		if err != nil {
+			return fmt.Errorf("failed to add payment input: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AddPayment pays satoshis to an address
+func (b *Builder) AddPayment(address string, satoshis uint64) error {
+	payAddr, err := parseAddress(address)
+	if err != nil {
+		return fmt.Errorf("failed to create payment address: %w", err)
+	}
+
+	lockingScript, err := p2pkh.Lock(payAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create payment script: %w", err)
+	}
+
+	b.addOutput(&transaction.TransactionOutput{
+		LockingScript: lockingScript,
+		Satoshis:      satoshis,
+	}, TxRolePayment)
+
+	return nil
+}
+
+// addPayments pays each of the payments
+func (b *Builder) addPayments(payments []*PayToAddress) error {
+	for _, payment := range payments {
+		err := b.AddPayment(payment.Address, payment.Satoshis)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SpendOrdinal adds a 1 sat ordinal owned by pk as an input
+// Use it with AddOrdinalOutput when ordinals and destinations aren't paired.
+func (b *Builder) SpendOrdinal(ordinal *NftUtxo, pk *ec.PrivateKey) error {
+	// Verify that ordinals have exactly 1 satoshi
+	if ordinal.Satoshis != 1 {
+		return fmt.Errorf("ordinal %s_%d: %w", ordinal.TxID, ordinal.Vout, ErrOrdinalNotOneSat)
+	}
+
+	unlocker, err := b.unlocker(pk)
+	if err != nil {
+		return fmt.Errorf("private key is required to sign the ordinal: %w", err)
+	}
+
+	err = b.addInput(&ordinal.Utxo, unlocker, TxRoleOrdinal)
+	if err != nil {
+		return fmt.Errorf("failed to add ordinal input: %w", err)
+	}
+
+	return nil
+}
+
+// AddOrdinalOutput adds a 1 sat output sending an ordinal to the destination
+// The output is a plain P2PKH unless the destination has an inscription.
+func (b *Builder) AddOrdinalOutput(destination *Destination) error {
+	return b.addDestination(destination, TxRoleOrdinal)
+}
+
+// AddOrdinal spends a 1 sat ordinal owned by pk and sends it to the destination
+// Ordinals follow their satoshi, so add them before any other input and output.
+func (b *Builder) AddOrdinal(ordinal *NftUtxo, pk *ec.PrivateKey, destination *Destination) error {
+	err := b.SpendOrdinal(ordinal, pk)
+	if err != nil {
+		return err
+	}
+
+	return b.AddOrdinalOutput(destination)
+}
+
+// AddInscription adds a 1 sat output inscribing a new ordinal for the destination
+func (b *Builder) AddInscription(destination *Destination) error {
+	// Validate destination has necessary data
+	if destination.Inscription == nil {
+		return fmt.Errorf("inscription is required for all destinations")
+	}
+
+	return b.addDestination(destination, TxRoleInscription)
+}
+
+// addDestination adds a 1 sat output to the destination with the given role
+func (b *Builder) addDestination(destination *Destination, role TxRole) error {
+	lockingScript, err := destinationScript(destination)
+	if err != nil {
+		return err
+	}
+
+	// Add the output to the transaction
+	b.addOutput(&transaction.TransactionOutput{
+		LockingScript: lockingScript,
+		Satoshis:      1, // 1 sat for ordinals
+	}, role)
+
+	return nil
+}
+
+// destinationScript creates the locking script of the 1 sat output to a destination
+func destinationScript(destination *Destination) (*script.Script, error) {
+	// Create the destination address
+	dstAddr, err := parseAddress(destination.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create destination address: %w", err)
+	}
+
+	if destination.OmitMetadata() || destination.Inscription == nil {
+		// Without an inscription, or if omitMetadata is enabled, use a simple P2PKH output
+		lockingScript, err := p2pkh.Lock(dstAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
+		}
+		return lockingScript, nil
+	}
+
+	// Create the ordinal P2PKH script with the inscription
+	ordP2pkh := &ordp2pkh.OrdP2PKH{
+		Inscription: destination.Inscription,
+		Address:     dstAddr,
+	}
+
+	// Get the locking script
+	lockingScript, err := ordP2pkh.Lock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ordp2pkh locking script: %w", err)
+	}
+
+	return lockingScript, nil
+}
+
+// AddTokenTransfer adds the token inputs and outputs of a BSV21 transfer
+// Only the token fields of the config are used, fund it with AddPaymentUtxos and finish with Change, Fee and Sign.
+// It returns the token inputs that were spent and the ones left untouched.
+func (b *Builder) AddTokenTransfer(config *TransferBsv21TokenConfig) (spent, unspent []*TokenUtxo, err error) {
+	return addTokenTransfer(b, config)
+}
+
+// AddOrdLock spends an ordinal owned by pk and lists it for sale with an Ordinal Lock
+// sellerAddress can cancel the listing, payAddress receives price satoshis when it is purchased.
+func (b *Builder) AddOrdLock(ordinal *NftUtxo, pk *ec.PrivateKey, sellerAddress, payAddress string, price uint64) error {
+	return b.addOrdLock(&ordinal.Utxo, pk, TxRoleOrdinal, nil, sellerAddress, payAddress, price)
+}
+
+// addOrdLock spends a 1 sat UTXO with the given role and adds the listing output
+// A non-nil token wraps the Ordinal Lock in a BSV21 transfer inscription, so token listings keep their tokens.
+func (b *Builder) addOrdLock(utxo *Utxo, pk *ec.PrivateKey, role TxRole, token *bsv21.Bsv21, sellerAddress, payAddress string, price uint64) error {
+	unlocker, err := b.unlocker(pk)
+	if err != nil {
+		return fmt.Errorf("private key is required to sign the listed input: %w", err)
+	}
+
+	err = b.addInput(utxo, unlocker, role)
+	if err != nil {
+		return fmt.Errorf("failed to add listed input: %w", err)
+	}
+
+	// Create seller address (for return on cancel)
+	sellerAddr, err := parseAddress(sellerAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create seller address: %w", err)
+	}
+
+	// Create pay address (where payment is sent)
+	payAddr, err := parseAddress(payAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create pay address: %w", err)
+	}
+
+	lockingScript, err := ordLockScript(sellerAddr, payAddr, price)
+	if err != nil {
+		return err
+	}
+
+	if token != nil {
+		lockingScript, err = token.Lock(lockingScript)
+		if err != nil {
+			return fmt.Errorf("failed to create token transfer script: %w", err)
+		}
+	}
+
+	// Add the output to the transaction
+	b.addOutput(&transaction.TransactionOutput{
+		LockingScript: lockingScript,
+		Satoshis:      1, // 1 sat for ordinals
+	}, TxRoleListing)
+
+	return nil
+}
+
+// AddOrdLockPurchase purchases an Ordinal Lock listing and sends the ordinal to the destination
+// The listing requires its payout right after the purchased ordinal, so it must be the first input
+// and output: the ordinal goes to output 0 and the price decoded from the listing to output 1.
+func (b *Builder) AddOrdLockPurchase(listing *NftUtxo, destination *Destination) error {
+	lockingScript, err := destinationScript(destination)
+	if err != nil {
+		return err
+	}
+
+	return b.addOrdLockPurchase(&listing.Utxo, TxRoleOrdinal, lockingScript)
+}
+
+// addOrdLockPurchase spends a listing and adds the buyer's output with the given role and the payout
+func (b *Builder) addOrdLockPurchase(utxo *Utxo, role TxRole, lockingScript *script.Script) error {
+	if len(b.tx.Inputs) > 0 || len(b.tx.Outputs) > 0 {
+		return fmt.Errorf("listing %s_%d must be purchased with the first input and output", utxo.TxID, utxo.Vout)
+	}
+
+	listingScript, err := script.NewFromHex(utxo.ScriptPubKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse listing script: %w", err)
+	}
+
+	listing := decodeOrdLock(listingScript)
+	if listing == nil {
+		return fmt.Errorf("listing %s_%d is not an Ordinal Lock", utxo.TxID, utxo.Vout)
+	}
+
+	payout := &transaction.TransactionOutput{}
+	if _, err = payout.ReadFrom(bytes.NewReader(listing.PayOut)); err != nil {
+		return fmt.Errorf("failed to read listing payout: %w", err)
+	}
+
+	err = b.addInput(utxo, &ordLockPurchase{}, TxRoleListing)
+	if err != nil {
+		return fmt.Errorf("failed to add listing input: %w", err)
+	}
+
+	b.addOutput(&transaction.TransactionOutput{
+		LockingScript: lockingScript,
+		Satoshis:      1, // 1 sat for ordinals
+	}, role)
+	b.addOutput(payout, TxRolePayment)
+
+	return nil
+}
+
+// CancelOrdLock cancels an Ordinal Lock listing of the seller pk and sends the ordinal to the destination
+func (b *Builder) CancelOrdLock(listing *NftUtxo, pk *ec.PrivateKey, destination *Destination) error {
+	lockingScript, err := destinationScript(destination)
+	if err != nil {
+		return err
+	}
+
+	return b.cancelOrdLock(&listing.Utxo, pk, TxRoleOrdinal, lockingScript)
+}
+
+// cancelOrdLock spends a listing of the seller pk and adds the returned output with the given role
+func (b *Builder) cancelOrdLock(utxo *Utxo, pk *ec.PrivateKey, role TxRole, lockingScript *script.Script) error {
+	// A dry run estimates the size without the key
+	if pk == nil && !b.dryRun {
+		return fmt.Errorf("private key is required to cancel the listing: %w", p2pkh.ErrNoPrivateKey)
+	}
+
+	err := b.addInput(utxo, &ordLockCancel{key: pk}, TxRoleListing)
+	if err != nil {
+		return fmt.Errorf("failed to add listing input: %w", err)
+	}
+
+	b.addOutput(&transaction.TransactionOutput{
+		LockingScript: lockingScript,
+		Satoshis:      1, // 1 sat for ordinals
+	}, role)
+
+	return nil
+}
+
+// AddOpReturn adds an unspendable OP_FALSE OP_RETURN output carrying data
+func (b *Builder) AddOpReturn(data ...[]byte) error {
+	lockingScript, err := opReturnScript(data...)
+	if err != nil {
+		return err
+	}
+
+	b.addOutput(&transaction.TransactionOutput{
+		LockingScript: lockingScript,
+		Satoshis:      0, // 0 sats for OP_RETURN
+	}, TxRoleOpReturn)
+
+	return nil
+}
+
+// opReturnScript builds an OP_FALSE OP_RETURN script pushing each piece of data
+func opReturnScript(data ...[]byte) (*script.Script, error) {
+	lockingScript := &script.Script{}
+	err := lockingScript.AppendOpcodes(script.OpFALSE, script.OpRETURN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OP_RETURN script: %w", err)
+	}
+
+	for _, d := range data {
+		err = lockingScript.AppendPushData(d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OP_RETURN script: %w", err)
+		}
+	}
+
+	return lockingScript, nil
+}
+
+// parseAddress parses an address string, wrapping failures with ErrInvalidAddress
+func parseAddress(address string) (*script.Address, error) {
+	addr, err := script.NewAddressFromString(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidAddress, address, err)
+	}
+
+	return addr, nil
+}
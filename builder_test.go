@@ -0,0 +1,324 @@
+package ordinals
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	"github.com/bitcoin-sv/go-templates/template/ordlock"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuilder tests composing a custom transaction from several operations
+func TestBuilder(t *testing.T) {
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	ordinal := &NftUtxo{
+		Utxo: Utxo{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+			Vout:         1,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     1,
+		},
+	}
+	paymentUtxos := []*Utxo{{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}}
+
+	t.Run("ordinal, tokens, payment and op return in one transaction", func(t *testing.T) {
+		b := NewBuilder(BuildOptions{FeeModel: &FixedFee{Satoshis: 100}})
+
+		// Ordinals go first so they keep their satoshi
+		require.NoError(t, b.AddOrdinal(ordinal, ordPk, &Destination{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"}))
+
+		spent, unspent, err := b.AddTokenTransfer(&TransferBsv21TokenConfig{
+			Protocol: TokenTypeBSV21,
+			TokenID:  tokenID,
+			InputTokens: []*TokenUtxo{{
+				Utxo: Utxo{
+					TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+					Vout:         0,
+					ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+					Satoshis:     1,
+				},
+				TokenID:  tokenID,
+				Protocol: TokenTypeBSV21,
+				Amount:   1000,
+			}},
+			Distributions: []*TokenDistribution{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Tokens: 400},
+			},
+			OrdPk: ordPk,
+		})
+		require.NoError(t, err)
+		assert.Len(t, spent, 1)
+		assert.Empty(t, unspent)
+
+		require.NoError(t, b.AddPaymentUtxos(paymentUtxos, paymentPk))
+		require.NoError(t, b.AddPayment("1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA", 5000))
+		require.NoError(t, b.AddOpReturn([]byte("hello"), []byte("world")))
+		require.NoError(t, b.Change("", paymentPk))
+		require.NoError(t, b.Fee(nil))
+
+		tx, err := b.Sign()
+		require.NoError(t, err)
+		assert.Same(t, b.Transaction(), tx)
+		for _, input := range tx.Inputs {
+			assert.NotNil(t, input.UnlockingScript)
+		}
+
+		plan := b.Plan()
+		roles := make([]TxRole, 0, len(plan.Outputs))
+		for _, output := range plan.Outputs {
+			roles = append(roles, output.Role)
+		}
+		assert.Equal(t, []TxRole{
+			TxRoleOrdinal, TxRoleToken, TxRoleTokenChange, TxRolePayment, TxRoleOpReturn, TxRoleChange,
+		}, roles)
+		assert.Equal(t, uint64(100), plan.Fee)
+		assert.Equal(t, uint64(100000+2-3-5000-100), plan.Change)
+
+		// The OP_RETURN pushes the data
+		assert.True(t, tx.Outputs[4].LockingScript.IsData())
+		assert.Equal(t, "006a0568656c6c6f05776f726c64", tx.Outputs[4].LockingScript.String())
+	})
+
+	t.Run("matches the send ordinals builder", func(t *testing.T) {
+		config := &SendOrdinalsConfig{
+			PaymentUtxos:  paymentUtxos,
+			Ordinals:      []*NftUtxo{ordinal},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+			Destinations:  []*Destination{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"}},
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		}
+
+		expected, err := SendOrdinals(config)
+		require.NoError(t, err)
+
+		b := NewBuilder(BuildOptions{})
+		require.NoError(t, b.SpendOrdinal(ordinal, ordPk))
+		require.NoError(t, b.AddPaymentUtxos(paymentUtxos, paymentPk))
+		require.NoError(t, b.AddOrdinalOutput(config.Destinations[0]))
+		require.NoError(t, b.Change(config.ChangeAddress, nil))
+		require.NoError(t, b.Fee(nil))
+		tx, err := b.Sign()
+		require.NoError(t, err)
+
+		// Signatures differ, the inputs and outputs don't
+		require.Equal(t, len(expected.Outputs), len(tx.Outputs))
+		for i, output := range expected.Outputs {
+			assert.Equal(t, output.Satoshis, tx.Outputs[i].Satoshis)
+			assert.Equal(t, output.LockingScript.String(), tx.Outputs[i].LockingScript.String())
+		}
+		for i, input := range expected.Inputs {
+			assert.Equal(t, input.SourceTXID.String(), tx.Inputs[i].SourceTXID.String())
+		}
+	})
+
+	t.Run("ord lock", func(t *testing.T) {
+		b := NewBuilder(BuildOptions{})
+		require.NoError(t, b.AddOrdLock(ordinal, ordPk, "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA", 10000))
+		require.NoError(t, b.AddPaymentUtxos(paymentUtxos, paymentPk))
+		require.NoError(t, b.Change("", paymentPk))
+		require.NoError(t, b.Fee(nil))
+		_, err := b.Sign()
+		require.NoError(t, err)
+
+		plan := b.Plan()
+		assert.Equal(t, TxRoleOrdinal, plan.Inputs[0].Role)
+		assert.Equal(t, TxRoleListing, plan.Outputs[0].Role)
+		assert.Equal(t, uint64(1), plan.Outputs[0].Satoshis)
+
+		listing := ordlock.Decode(b.tx.Outputs[0].LockingScript)
+		require.NotNil(t, listing)
+		seller, err := parseAddress("1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE")
+		require.NoError(t, err)
+		assert.Equal(t, seller.PublicKeyHash, listing.Seller.PublicKeyHash)
+		assert.Equal(t, uint64(10000), listing.Price)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		b := NewBuilder(BuildOptions{})
+
+		err := b.AddPayment("invalid-address", 1000)
+		assert.ErrorIs(t, err, ErrInvalidAddress)
+
+		notOneSat := *ordinal
+		notOneSat.Satoshis = 2
+		err = b.SpendOrdinal(&notOneSat, ordPk)
+		assert.ErrorIs(t, err, ErrOrdinalNotOneSat)
+
+		err = b.AddInscription(&Destination{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"})
+		assert.Error(t, err)
+
+		err = b.Change("", nil)
+		assert.ErrorIs(t, err, ErrNoChangeAddress)
+	})
+
+	t.Run("change to payment key address", func(t *testing.T) {
+		b := NewBuilder(BuildOptions{})
+		require.NoError(t, b.AddPaymentUtxos(paymentUtxos, paymentPk))
+		require.NoError(t, b.Change("", paymentPk))
+		require.NoError(t, b.Fee(nil))
+
+		paymentAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+		require.NoError(t, err)
+		changeAddr, err := b.Transaction().Outputs[0].LockingScript.Address()
+		require.NoError(t, err)
+		assert.Equal(t, paymentAddr.AddressString, changeAddr.AddressString)
+	})
+}
+
+// TestBuilderInputs tests rejecting outpoints spent twice and ordinals spent as payments
+func TestBuilderInputs(t *testing.T) {
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	payment := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}
+
+	// lockedScript returns the hex of a P2PKH script carrying an inscription or token
+	lockedScript := func(t *testing.T, token bool) string {
+		p2pkhScript, err := script.NewFromHex("76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac")
+		require.NoError(t, err)
+
+		var lockingScript *script.Script
+		if token {
+			lockingScript, err = (&bsv21.Bsv21{Op: string(bsv21.OpTransfer), Id: tokenID, Amt: 10}).Lock(p2pkhScript)
+		} else {
+			lockingScript, err = (&inscription.Inscription{
+				File:         inscription.File{Content: []byte("hello"), Type: "text/plain"},
+				ScriptSuffix: *p2pkhScript,
+			}).Lock()
+		}
+		require.NoError(t, err)
+		return lockingScript.String()
+	}
+
+	t.Run("payment spent as an ordinal", func(t *testing.T) {
+		_, err := SendOrdinals(&SendOrdinalsConfig{
+			PaymentUtxos:  []*Utxo{payment},
+			Ordinals:      []*NftUtxo{{Utxo: Utxo{TxID: payment.TxID, Vout: payment.Vout, ScriptPubKey: payment.ScriptPubKey, Satoshis: 1}}},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+			Destinations:  []*Destination{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"}},
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		})
+		assert.ErrorIs(t, err, ErrDuplicateOutpoint)
+	})
+
+	t.Run("token input listed twice", func(t *testing.T) {
+		tokenUtxo := &TokenUtxo{
+			Utxo: Utxo{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+				Vout:         0,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     1,
+			},
+			TokenID:  tokenID,
+			Protocol: TokenTypeBSV21,
+			Amount:   100,
+		}
+
+		_, err := TransferOrdTokens(&TransferBsv21TokenConfig{
+			Protocol:      TokenTypeBSV21,
+			TokenID:       tokenID,
+			Utxos:         []*Utxo{payment},
+			InputTokens:   []*TokenUtxo{tokenUtxo, tokenUtxo},
+			Distributions: []*TokenDistribution{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Tokens: 150}},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+		})
+		assert.ErrorIs(t, err, ErrDuplicateOutpoint)
+	})
+
+	t.Run("ordinals spent as payments", func(t *testing.T) {
+		for _, token := range []bool{false, true} {
+			inscribed := *payment
+			inscribed.ScriptPubKey = lockedScript(t, token)
+
+			b := NewBuilder(BuildOptions{})
+			err := b.AddPaymentUtxos([]*Utxo{&inscribed}, paymentPk)
+			assert.ErrorIs(t, err, ErrOrdinalPayment)
+
+			err = Validate(&SendUtxosConfig{
+				Utxos:     []*Utxo{&inscribed},
+				PaymentPk: paymentPk,
+				Payments:  []*PayToAddress{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 1000}},
+			})
+			assert.ErrorIs(t, err, ErrOrdinalPayment)
+		}
+
+		// The same script is fine as an ordinal
+		b := NewBuilder(BuildOptions{})
+		err := b.SpendOrdinal(&NftUtxo{Utxo: Utxo{
+			TxID:         payment.TxID,
+			ScriptPubKey: lockedScript(t, false),
+			Satoshis:     1,
+		}}, ordPk)
+		assert.NoError(t, err)
+	})
+}
+
+// TestBuilderLogger tests debug logs of input, output and fee decisions
+func TestBuilderLogger(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err = SendUtxos(&SendUtxosConfig{
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     100000,
+		}},
+		Payments:     []*PayToAddress{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 1000}},
+		PaymentPk:    paymentPk,
+		BuildOptions: BuildOptions{FeeModel: &FixedFee{Satoshis: 100}, Logger: logger},
+	})
+	require.NoError(t, err)
+
+	output := logs.String()
+	assert.Contains(t, output, `msg="added input" index=0 outpoint=0000000000000000000000000000000000000000000000000000000000000003_0 satoshis=100000 role=payment`)
+	assert.Contains(t, output, `msg="added output" index=0 satoshis=1000 role=payment`)
+	assert.Contains(t, output, `msg="added output" index=1 satoshis=0 role=change`)
+	assert.Contains(t, output, `msg="calculated fee" fee=100`)
+	assert.Contains(t, output, "change=98900")
+
+	// Screened payments are logged before they are rejected
+	logs.Reset()
+	b := NewBuilder(BuildOptions{Logger: logger})
+	err = b.AddPaymentUtxos([]*Utxo{{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     1,
+	}}, paymentPk)
+	require.ErrorIs(t, err, ErrOrdinalPayment)
+	assert.Contains(t, logs.String(), `msg="rejected payment input"`)
+}
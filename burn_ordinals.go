@@ -1,15 +1,13 @@
 package ordinals
 
 import (
-	"encoding/hex"
+	"errors"
 	"fmt"
-	"strings"
+	"sort"
 
 	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/bsv-blockchain/go-sdk/transaction"
-	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
-	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
 )
 
 // BurnOrdinalsConfig represents configuration for burning ordinals
@@ -28,146 +26,99 @@ type BurnOrdinalsConfig struct {
 	ChangeAddress string
 	// SatsPerKb is the fee rate in satoshis per kilobyte
 	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // BurnOrdinals burns ordinals by consuming them as fees
 // It creates a transaction that spends the ordinal UTXOs and adds an optional
 // OP_RETURN output with MAP protocol metadata
 func BurnOrdinals(config *BurnOrdinalsConfig) (*transaction.Transaction, error) {
-	// Create a new transaction
-	tx := transaction.NewTransaction()
+	b := newTxBuilder(false)
+	if err := buildBurnOrdinals(b, config); err != nil {
+		return nil, err
+	}
 
-	// Add payment inputs
-	for _, utxo := range config.PaymentUtxos {
-		unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create payment unlocker: %w", err)
-		}
+	return b.tx, nil
+}
 
-		err = tx.AddInputFrom(
-			utxo.TxID,
-			utxo.Vout,
-			utxo.ScriptPubKey,
-			utxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add payment input: %w", err)
-		}
+// buildBurnOrdinals adds the inputs and outputs of BurnOrdinals to the builder
+func buildBurnOrdinals(b *Builder, config *BurnOrdinalsConfig) error {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
+
+	// Add payment inputs
+	err := b.AddPaymentUtxos(config.PaymentUtxos, config.PaymentPk)
+	if err != nil {
+		return err
 	}
 
 	// Add ordinal inputs
 	for _, ordUtxo := range config.Ordinals {
-		// Check that it's a 1-sat ordinal
-		if ordUtxo.Satoshis != 1 {
-			return nil, fmt.Errorf("1Sat Ordinal UTXOs must have exactly 1 satoshi")
-		}
-
-		// Create the unlocker
-		unlocker, err := p2pkh.Unlock(config.OrdPk, nil)
+		err = b.SpendOrdinal(ordUtxo, config.OrdPk)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create ordinal unlocker: %w", err)
-		}
-
-		// Add the input
-		err = tx.AddInputFrom(
-			ordUtxo.TxID,
-			ordUtxo.Vout,
-			ordUtxo.ScriptPubKey,
-			ordUtxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add ordinal input: %w", err)
+			return err
 		}
 	}
 
-	// Add OP_RETURN output with metadata if provided
+	// Add OP_RETURN output, with MAP metadata if provided
+	var data [][]byte
 	if len(config.Metadata) > 0 {
-		// Build a simple OP_RETURN script with MAP metadata
-		asm := "OP_FALSE OP_RETURN "
-
-		// Add MAP prefix
-		mapPrefix := []byte(MAP_PREFIX)
-		asm += hex.EncodeToString(mapPrefix) + " "
-
-		// Add SET command
-		asm += hex.EncodeToString([]byte("SET")) + " "
-
-		// Add metadata entries
-		for key, value := range config.Metadata {
-			asm += hex.EncodeToString([]byte(key)) + " "
-			asm += hex.EncodeToString(value) + " "
-		}
-
-		// Remove trailing space
-		asm = strings.TrimSpace(asm)
-
-		// Create the script
-		opReturnScript, err := script.NewFromASM(asm)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create OP_RETURN script: %w", err)
-		}
-
-		// Add output with the inscription script
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: opReturnScript,
-			Satoshis:      0, // 0 sats for OP_RETURN
-		})
-	} else {
-		// Create a simple OP_FALSE OP_RETURN output
-		scriptAsm, err := script.NewFromASM("OP_FALSE OP_RETURN")
-		if err != nil {
-			return nil, fmt.Errorf("failed to create OP_RETURN script: %w", err)
-		}
-
-		// Add output with the simple OP_RETURN script
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: scriptAsm,
-			Satoshis:      0,
-		})
+		data = mapOpReturnData(config.Metadata)
 	}
 
-	// Add change output if needed
-	if config.ChangeAddress != "" {
-		changeAddr, err := script.NewAddressFromString(config.ChangeAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change address: %w", err)
-		}
-
-		changeScript, err := p2pkh.Lock(changeAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change script: %w", err)
-		}
-
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: changeScript,
-			Change:        true,
-		})
+	err = b.AddOpReturn(data...)
+	if err != nil {
+		return err
 	}
 
-	// Set the fee rate
-	feeRate := DEFAULT_SAT_PER_KB
-	if config.SatsPerKb > 0 {
-		feeRate = config.SatsPerKb
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return fmt.Errorf("failed to add change output: %w", err)
 	}
 
-	// Create fee model
-	feeModel := &feemodel.SatoshisPerKilobyte{
-		Satoshis: feeRate,
-	}
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
 
 	// Calculate fee
-	err := tx.Fee(feeModel, transaction.ChangeDistributionEqual)
+	err = b.Fee(feeModel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate fee: %w", err)
+		if errors.Is(err, ErrInsufficientFunds) {
+			return fmt.Errorf("not enough funds to burn ordinals: %w", err)
+		}
+		return fmt.Errorf("failed to calculate fee: %w", err)
 	}
 
 	// Sign the transaction
-	err = tx.Sign()
+	_, err = b.Sign()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return nil
+}
+
+// createMapOpReturnScript builds an OP_FALSE OP_RETURN script carrying MAP SET metadata
+func createMapOpReturnScript(metadata map[string][]byte) (*script.Script, error) {
+	return opReturnScript(mapOpReturnData(metadata)...)
+}
+
+// mapOpReturnData returns the MAP SET pushes for the metadata
+func mapOpReturnData(metadata map[string][]byte) [][]byte {
+	// Add MAP prefix and SET command
+	data := [][]byte{[]byte(MAP_PREFIX), []byte("SET")}
+
+	// Add metadata entries in a stable order
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		data = append(data, []byte(key), metadata[key])
 	}
 
-	return tx, nil
+	return data
 }
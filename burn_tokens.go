@@ -0,0 +1,73 @@
+package ordinals
+
+import (
+	"fmt"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// BurnOrdTokensConfig represents configuration for burning tokens
+type BurnOrdTokensConfig struct {
+	// Protocol is the token protocol (e.g., TokenTypeBSV21)
+	Protocol TokenType
+	// TokenID is the ID of the token to burn
+	TokenID string
+	// PaymentUtxos is the list of UTXOs to use for paying the fee
+	PaymentUtxos []*Utxo
+	// PaymentPk is the private key for the payment UTXOs
+	PaymentPk *ec.PrivateKey
+	// InputTokens is the list of token UTXOs to burn from
+	InputTokens []*TokenUtxo
+	// OrdPk is the private key for the token UTXOs
+	OrdPk *ec.PrivateKey
+	// Amount is the raw amount of tokens to burn, zero burns every input token
+	// Convert a display amount with FromToken. Any tokens not burned are returned to the OrdPk address as change.
+	Amount uint64
+	// Metadata is optional MAP protocol metadata stating the reason for the burn
+	Metadata map[string][]byte
+	// ChangeAddress is the address to send any change to
+	ChangeAddress string
+	// SatsPerKb is the fee rate in satoshis per kilobyte
+	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
+	// Decimals is the number of decimal places for the token
+	Decimals uint8
+}
+
+// BurnOrdTokens burns tokens with an explicit burn operation
+// It creates a transaction that spends the token UTXOs, records the burned amount
+// with the protocol's burn operation, adds an optional OP_RETURN output with
+// MAP protocol metadata and returns any unburned tokens as change
+func BurnOrdTokens(config *BurnOrdTokensConfig) (*transaction.Transaction, error) {
+	transferConfig, err := burnTransferConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return TransferOrdTokens(transferConfig)
+}
+
+// burnTransferConfig converts a burn into the token transfer that performs it
+func burnTransferConfig(config *BurnOrdTokensConfig) (*TransferBsv21TokenConfig, error) {
+	if len(config.InputTokens) == 0 {
+		return nil, fmt.Errorf("at least one token UTXO is required")
+	}
+
+	return &TransferBsv21TokenConfig{
+		Protocol:      config.Protocol,
+		TokenID:       config.TokenID,
+		Utxos:         config.PaymentUtxos,
+		InputTokens:   config.InputTokens,
+		PaymentPk:     config.PaymentPk,
+		OrdPk:         config.OrdPk,
+		BurnRemaining: config.Amount == 0,
+		BurnTokens:    config.Amount,
+		BurnMetadata:  config.Metadata,
+		ChangeAddress: config.ChangeAddress,
+		SatsPerKb:     config.SatsPerKb,
+		BuildOptions:  config.BuildOptions,
+		Decimals:      config.Decimals,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package ordinals
+
+import (
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// ChainStep builds one transaction of a chain, funded by utxos
+// The first step gets the UTXOs passed to Chain, every later step the change of the step before.
+type ChainStep func(utxos []*Utxo) (*transaction.Transaction, error)
+
+// ChainResult is the outcome of Chain
+type ChainResult struct {
+	// Transactions are the signed transactions, broadcast them in this order
+	Transactions []*transaction.Transaction
+	// Utxos is the change of the last transaction, ready to fund the next build
+	Utxos []*Utxo
+}
+
+// Chain builds dependent transactions, each funded by the unconfirmed change of the one before
+// No UTXOs need to be fetched between steps, so the whole chain can be built before anything is broadcast.
+func Chain(utxos []*Utxo, steps ...ChainStep) (*ChainResult, error) {
+	result := &ChainResult{Utxos: utxos}
+
+	for i, step := range steps {
+		if i > 0 && len(result.Utxos) == 0 {
+			return nil, fmt.Errorf("chained transaction %d left no payment change to fund the next transaction", i-1)
+		}
+
+		tx, err := step(result.Utxos)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build chained transaction %d: %w", i, err)
+		}
+
+		result.Transactions = append(result.Transactions, tx)
+		result.Utxos = ChangeUtxos(tx)
+	}
+
+	return result, nil
+}
+
+// chainedChangeAddress returns the change address of a transaction in a chain
+// The change of every transaction but the last funds the next one, which signs it with the
+// payment key, so it goes to the payment key address ("" derives it from PaymentPk).
+// Only the last transaction sends its change to changeAddress.
+func chainedChangeAddress(changeAddress string, last bool) string {
+	if last {
+		return changeAddress
+	}
+
+	return ""
+}
+
+// ChangeUtxos returns the payment change outputs of a signed transaction as UTXOs
+// With ChangeSplit there can be several. They can fund the next transaction before this one is mined.
+func ChangeUtxos(tx *transaction.Transaction) []*Utxo {
+	var utxos []*Utxo
+	txid := tx.TxID().String()
+	for vout, output := range tx.Outputs {
+		if output.Change && output.Satoshis > 0 {
+			utxos = append(utxos, &Utxo{
+				TxID:         txid,
+				Vout:         uint32(vout),
+				ScriptPubKey: output.LockingScript.String(),
+				Satoshis:     output.Satoshis,
+			})
+		}
+	}
+
+	return utxos
+}
+
+// ChangeUtxos returns the payment change outputs of the transaction as UTXOs
+// Call it after Sign, signing changes the transaction ID.
+func (b *Builder) ChangeUtxos() []*Utxo {
+	return ChangeUtxos(b.tx)
+}
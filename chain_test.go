@@ -0,0 +1,84 @@
+package ordinals
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChain tests building dependent transactions from unconfirmed change
+func TestChain(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	payAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	require.NoError(t, err)
+
+	// inscribe builds a step inscribing text to the payment address
+	inscribe := func(text string) ChainStep {
+		return func(utxos []*Utxo) (*transaction.Transaction, error) {
+			return CreateOrdinals(&CreateOrdinalsConfig{
+				Utxos: utxos,
+				Destinations: []*Destination{{
+					Address:     payAddr.AddressString,
+					Inscription: &inscription.Inscription{File: inscription.File{Content: []byte(text), Type: "text/plain"}},
+				}},
+				PaymentPk: paymentPk,
+			})
+		}
+	}
+
+	t.Run("change funds the next transaction", func(t *testing.T) {
+		var steps []ChainStep
+		for i := 0; i < 5; i++ {
+			steps = append(steps, inscribe(fmt.Sprintf("inscription %d", i)))
+		}
+
+		result, err := Chain(walletUtxos(t, payAddr, 1, 100000), steps...)
+		require.NoError(t, err)
+		require.Len(t, result.Transactions, 5)
+
+		for i := 1; i < len(result.Transactions); i++ {
+			prev := result.Transactions[i-1]
+			tx := result.Transactions[i]
+			require.Len(t, tx.Inputs, 1)
+			assert.Equal(t, prev.TxID().String(), tx.Inputs[0].SourceTXID.String())
+			assert.True(t, prev.Outputs[tx.Inputs[0].SourceTxOutIndex].Change)
+		}
+
+		last := result.Transactions[len(result.Transactions)-1]
+		assert.Equal(t, ChangeUtxos(last), result.Utxos)
+		require.Len(t, result.Utxos, 1)
+		assert.Less(t, result.Utxos[0].Satoshis, uint64(100000-5))
+	})
+
+	t.Run("failing step", func(t *testing.T) {
+		failure := errors.New("failure")
+		_, err := Chain(walletUtxos(t, payAddr, 1, 100000), inscribe("first"), func([]*Utxo) (*transaction.Transaction, error) {
+			return nil, failure
+		})
+		require.ErrorIs(t, err, failure)
+		assert.Contains(t, err.Error(), "chained transaction 1")
+	})
+
+	t.Run("builder change", func(t *testing.T) {
+		b := NewBuilder(BuildOptions{})
+		require.NoError(t, b.AddPaymentUtxos(walletUtxos(t, payAddr, 1, 100000), paymentPk))
+		require.NoError(t, b.AddPayment(payAddr.AddressString, 1000))
+		require.NoError(t, b.Change("", paymentPk))
+		require.NoError(t, b.Fee(nil))
+		tx, err := b.Sign()
+		require.NoError(t, err)
+
+		changeUtxos := b.ChangeUtxos()
+		require.Len(t, changeUtxos, 1)
+		assert.Equal(t, tx.TxID().String(), changeUtxos[0].TxID)
+		assert.Equal(t, uint32(1), changeUtxos[0].Vout)
+	})
+}
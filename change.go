@@ -0,0 +1,230 @@
+package ordinals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+)
+
+// Change adds the payment change outputs
+// Change goes to changeAddress, or the address of paymentPk if it is empty.
+// Without either it fails with ErrNoChangeAddress unless AllowNoChange is set.
+// With a ChangeSplit several outputs are added and the change is divided between them by Fee.
+func (b *Builder) Change(changeAddress string, paymentPk *ec.PrivateKey) error {
+	lockingScript, err := b.changeScript(changeAddress, paymentPk)
+	if err != nil {
+		return err
+	}
+	if lockingScript == nil {
+		b.log().Debug("no change output, leftover satoshis go to the miner")
+		return nil
+	}
+
+	for i := 0; i < b.options.ChangeSplit.outputs(); i++ {
+		b.addOutput(&transaction.TransactionOutput{
+			LockingScript: lockingScript,
+			Change:        true,
+		}, TxRoleChange)
+	}
+
+	return nil
+}
+
+// changeScript returns the locking script for payment change, or nil if there is no change
+func (b *Builder) changeScript(changeAddress string, paymentPk *ec.PrivateKey) (*script.Script, error) {
+	var changeAddr *script.Address
+	var err error
+
+	switch {
+	case changeAddress != "":
+		changeAddr, err = parseAddress(changeAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create change address: %w", err)
+		}
+	case paymentPk != nil:
+		// Derive the change address from the payment key
+		changeAddr, err = script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create change address: %w", err)
+		}
+	case b.options.AllowNoChange:
+		return nil, nil
+	case b.dryRun:
+		// A dry run without the key uses a placeholder of the same size
+		return placeholderScript(), nil
+	default:
+		return nil, ErrNoChangeAddress
+	}
+
+	changeScript, err := p2pkh.Lock(changeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create change script: %w", err)
+	}
+
+	return changeScript, nil
+}
+
+// Fee calculates the fee, distributes the change and checks the fee against MaxFee
+// A nil feeModel uses the FeeModel from the build options, or DEFAULT_SAT_PER_KB.
+// A DynamicFee fetches its rate once per call.
+func (b *Builder) Fee(feeModel transaction.FeeModel) error {
+	if feeModel == nil {
+		feeModel = b.options.feeModel(0)
+	}
+
+	feeModel, err := resolveFeeModel(feeModel)
+	if err != nil {
+		return err
+	}
+
+	if b.options.ChangeSplit == nil {
+		err = applyFee(b.tx, feeModel)
+	} else {
+		err = b.applySplitFee(feeModel)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = b.checkFee(); err != nil {
+		b.log().Debug("rejected fee", "error", err)
+		return err
+	}
+
+	b.logFee()
+	return nil
+}
+
+// logFee logs the fee and change of the transaction
+func (b *Builder) logFee() {
+	log := b.log()
+	if !log.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	var totalIn, fee, change uint64
+	for _, input := range b.tx.Inputs {
+		if sats := input.SourceTxSatoshis(); sats != nil {
+			totalIn += *sats
+		}
+	}
+	if totalOut := b.tx.TotalOutputSatoshis(); totalIn > totalOut {
+		fee = totalIn - totalOut
+	}
+	for _, output := range b.changeOutputs() {
+		change += output.Satoshis
+	}
+
+	log.Debug("calculated fee",
+		"fee", fee,
+		"size", estimateTxSize(b.tx),
+		"inputs", len(b.tx.Inputs),
+		"outputs", len(b.tx.Outputs),
+		"change", change,
+	)
+}
+
+// checkFee refuses a transaction paying more than MaxFee
+// Without a change output all leftover satoshis go to the miner, so DEFAULT_MAX_NO_CHANGE_FEE
+// applies unless AllowNoChange is set.
+func (b *Builder) checkFee() error {
+	totalIn, err := b.tx.TotalInputSatoshis()
+	if err != nil {
+		return fmt.Errorf("failed to total inputs: %w", err)
+	}
+	totalOut := b.tx.TotalOutputSatoshis()
+	if totalIn < totalOut {
+		return nil
+	}
+	fee := totalIn - totalOut
+
+	// An explicit limit always applies
+	if b.options.MaxFee > 0 {
+		if fee > b.options.MaxFee {
+			return fmt.Errorf("%w: %d satoshis is above the limit of %d", ErrFeeTooHigh, fee, b.options.MaxFee)
+		}
+		return nil
+	}
+
+	// Change collects the leftover satoshis
+	if len(b.changeOutputs()) > 0 || b.options.AllowNoChange {
+		return nil
+	}
+
+	if fee > DEFAULT_MAX_NO_CHANGE_FEE {
+		return fmt.Errorf("%w: %d satoshis would be paid without a change output, above the limit of %d", ErrFeeTooHigh, fee, DEFAULT_MAX_NO_CHANGE_FEE)
+	}
+
+	return nil
+}
+
+// applyFee calculates the fee and distributes change equally across the change outputs
+// If the inputs can't cover the outputs and fee an InsufficientFundsError is returned
+func applyFee(tx *transaction.Transaction, feeModel transaction.FeeModel) error {
+	// Without change outputs only check the inputs cover the fee, go-sdk can't distribute to zero outputs
+	if !hasChangeOutput(tx) {
+		return checkInputsCoverFee(tx, feeModel)
+	}
+
+	err := tx.Fee(feeModel, transaction.ChangeDistributionEqual)
+	if errors.Is(err, transaction.ErrInsufficientInputs) {
+		return newInsufficientFundsError(tx, feeModel)
+	}
+
+	return err
+}
+
+// hasChangeOutput reports whether the transaction has a change output
+func hasChangeOutput(tx *transaction.Transaction) bool {
+	for _, output := range tx.Outputs {
+		if output.Change {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkInputsCoverFee returns an InsufficientFundsError if the inputs can't cover the outputs and fee
+func checkInputsCoverFee(tx *transaction.Transaction, feeModel transaction.FeeModel) error {
+	if _, err := feeModel.ComputeFee(tx); err != nil {
+		return err
+	}
+
+	fundsErr := newInsufficientFundsError(tx, feeModel)
+	if fundsErr.Available < fundsErr.Needed {
+		return fundsErr
+	}
+
+	return nil
+}
+
+// newInsufficientFundsError calculates the satoshis needed and available for a transaction
+func newInsufficientFundsError(tx *transaction.Transaction, feeModel transaction.FeeModel) *InsufficientFundsError {
+	fundsErr := &InsufficientFundsError{}
+
+	// Sum the inputs
+	for _, input := range tx.Inputs {
+		if sats := input.SourceTxSatoshis(); sats != nil {
+			fundsErr.Available += *sats
+		}
+	}
+
+	// Sum the outputs that aren't change and add the fee
+	for _, output := range tx.Outputs {
+		if !output.Change {
+			fundsErr.Needed += output.Satoshis
+		}
+	}
+	if fee, err := feeModel.ComputeFee(tx); err == nil {
+		fundsErr.Needed += fee
+	}
+
+	return fundsErr
+}
@@ -0,0 +1,156 @@
+package ordinals
+
+import (
+	"math/rand"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// ChangeSplit splits payment change into several outputs
+// This leaves many spendable UTXOs so independent transactions can be chained in parallel.
+type ChangeSplit struct {
+	// Outputs is the number of change outputs to create, 0 or 1 creates a single output
+	Outputs int
+	// Randomize gives each output a random share of the change instead of an equal share
+	Randomize bool
+	// MinSatoshis is the smallest change output to create, defaults to 1
+	// Outputs are dropped until every remaining output can hold at least this much.
+	MinSatoshis uint64
+}
+
+// outputs returns the number of change outputs to create
+func (c *ChangeSplit) outputs() int {
+	if c == nil || c.Outputs < 1 {
+		return 1
+	}
+
+	return c.Outputs
+}
+
+// minSatoshis returns the smallest change output to create
+func (c *ChangeSplit) minSatoshis() uint64 {
+	if c == nil || c.MinSatoshis == 0 {
+		return 1
+	}
+
+	return c.MinSatoshis
+}
+
+// applySplitFee calculates the fee and splits the change according to b.options.ChangeSplit
+// Unlike the go-sdk distribution no satoshis are lost to rounding, any remainder goes to the change.
+func (b *Builder) applySplitFee(feeModel transaction.FeeModel) error {
+	// Sum the inputs and the outputs that aren't change
+	var totalIn, totalOut uint64
+	for _, input := range b.tx.Inputs {
+		if sats := input.SourceTxSatoshis(); sats != nil {
+			totalIn += *sats
+		}
+	}
+	for _, output := range b.tx.Outputs {
+		if !output.Change {
+			totalOut += output.Satoshis
+		}
+	}
+
+	minSats := b.options.ChangeSplit.minSatoshis()
+	for {
+		fee, err := feeModel.ComputeFee(b.tx)
+		if err != nil {
+			return err
+		}
+
+		if totalIn < totalOut+fee {
+			return newInsufficientFundsError(b.tx, feeModel)
+		}
+		change := totalIn - totalOut - fee
+
+		// Drop a change output if the change can't cover the minimum of every output
+		// The fee is recalculated as the transaction is now smaller
+		changeOutputs := b.changeOutputs()
+		if len(changeOutputs) == 0 {
+			return nil
+		}
+		if change < uint64(len(changeOutputs))*minSats {
+			b.removeOutput(changeOutputs[len(changeOutputs)-1])
+			continue
+		}
+
+		// Distribute the change
+		amounts := splitChange(change, len(changeOutputs), minSats, b.options.ChangeSplit.Randomize)
+		for i, output := range changeOutputs {
+			output.Satoshis = amounts[i]
+		}
+
+		return nil
+	}
+}
+
+// changeOutputs returns the payment change outputs in order
+func (b *Builder) changeOutputs() []*transaction.TransactionOutput {
+	var outputs []*transaction.TransactionOutput
+	for _, output := range b.tx.Outputs {
+		if output.Change {
+			outputs = append(outputs, output)
+		}
+	}
+
+	return outputs
+}
+
+// removeOutput removes an output from the transaction
+func (b *Builder) removeOutput(output *transaction.TransactionOutput) {
+	for i, o := range b.tx.Outputs {
+		if o == output {
+			b.tx.Outputs = append(b.tx.Outputs[:i], b.tx.Outputs[i+1:]...)
+			break
+		}
+	}
+
+	delete(b.outputRoles, output)
+}
+
+// splitChange divides change into outputs amounts of at least minSats each
+// The caller must ensure change >= outputs * minSats.
+func splitChange(change uint64, outputs int, minSats uint64, randomize bool) []uint64 {
+	amounts := make([]uint64, outputs)
+	for i := range amounts {
+		amounts[i] = minSats
+	}
+	remaining := change - uint64(outputs)*minSats
+
+	if randomize {
+		// Give each output a random share of the remainder above the minimum
+		weights := make([]uint64, outputs)
+		var totalWeight uint64
+		for i := range weights {
+			weights[i] = uint64(rand.Intn(1000) + 1)
+			totalWeight += weights[i]
+		}
+
+		var allocated uint64
+		for i := range amounts {
+			share := uint64(float64(remaining) * float64(weights[i]) / float64(totalWeight))
+			if allocated+share > remaining {
+				share = remaining - allocated
+			}
+			amounts[i] += share
+			allocated += share
+		}
+
+		// Rounding leftovers go to a random output
+		amounts[rand.Intn(outputs)] += remaining - allocated
+		return amounts
+	}
+
+	// Equal shares, the remainder is spread over the first outputs one satoshi each
+	share := remaining / uint64(outputs)
+	extra := remaining % uint64(outputs)
+	for i := range amounts {
+		amounts[i] += share
+		if uint64(i) < extra {
+			amounts[i]++
+		}
+	}
+
+	return amounts
+}
@@ -0,0 +1,239 @@
+package ordinals
+
+import (
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitChange tests dividing change into several amounts
+func TestSplitChange(t *testing.T) {
+	tests := []struct {
+		name      string
+		change    uint64
+		outputs   int
+		minSats   uint64
+		randomize bool
+		expected  []uint64
+	}{
+		{
+			name:     "even split",
+			change:   1000,
+			outputs:  4,
+			minSats:  1,
+			expected: []uint64{250, 250, 250, 250},
+		},
+		{
+			name:     "remainder is not lost",
+			change:   1003,
+			outputs:  4,
+			minSats:  1,
+			expected: []uint64{251, 251, 251, 250},
+		},
+		{
+			name:     "exactly the minimum",
+			change:   300,
+			outputs:  3,
+			minSats:  100,
+			expected: []uint64{100, 100, 100},
+		},
+		{
+			name:      "randomized",
+			change:    100000,
+			outputs:   10,
+			minSats:   546,
+			randomize: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts := splitChange(tt.change, tt.outputs, tt.minSats, tt.randomize)
+			require.Len(t, amounts, tt.outputs)
+
+			var total uint64
+			for _, amount := range amounts {
+				assert.GreaterOrEqual(t, amount, tt.minSats)
+				total += amount
+			}
+			assert.Equal(t, tt.change, total)
+
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, amounts)
+			}
+		})
+	}
+}
+
+// TestChangeSplit tests splitting change through a real builder
+func TestChangeSplit(t *testing.T) {
+	// changeOutputs returns the change outputs and their total
+	changeOutputs := func(plan *TxPlan) (int, uint64) {
+		var count int
+		var total uint64
+		for _, output := range plan.Outputs {
+			if output.Role == TxRoleChange {
+				count++
+				total += output.Satoshis
+			}
+		}
+		return count, total
+	}
+
+	t.Run("split into several outputs", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.ChangeSplit = &ChangeSplit{Outputs: 5}
+
+		tx, err := SendUtxos(config)
+		require.NoError(t, err)
+		require.Len(t, tx.Outputs, 6)
+
+		// Every output gets the same share, the fee covers the larger transaction
+		plan, err := EstimateSendUtxos(config)
+		require.NoError(t, err)
+		count, total := changeOutputs(plan)
+		assert.Equal(t, 5, count)
+		assert.Equal(t, uint64(100000-5000)-plan.Fee, total)
+		for _, output := range tx.Outputs[1:] {
+			assert.InDelta(t, float64(total)/5, float64(output.Satoshis), 1)
+		}
+	})
+
+	t.Run("randomized", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.ChangeSplit = &ChangeSplit{Outputs: 8, Randomize: true, MinSatoshis: 1000}
+
+		plan, err := EstimateSendUtxos(config)
+		require.NoError(t, err)
+
+		count, total := changeOutputs(plan)
+		assert.Equal(t, 8, count)
+		assert.Equal(t, uint64(100000-5000)-plan.Fee, total)
+		for _, output := range plan.Outputs[1:] {
+			assert.GreaterOrEqual(t, output.Satoshis, uint64(1000))
+		}
+	})
+
+	t.Run("minimum drops outputs", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.ChangeSplit = &ChangeSplit{Outputs: 10, MinSatoshis: 30000}
+
+		plan, err := EstimateSendUtxos(config)
+		require.NoError(t, err)
+
+		// 95000 satoshis of change can only fill 3 outputs of 30000
+		count, total := changeOutputs(plan)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, uint64(100000-5000)-plan.Fee, total)
+	})
+
+	t.Run("change below minimum goes to fee", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.ChangeSplit = &ChangeSplit{Outputs: 2, MinSatoshis: 200000}
+
+		// The leftover is above the default limit for transactions without change
+		_, err := EstimateSendUtxos(config)
+		require.ErrorIs(t, err, ErrFeeTooHigh)
+
+		config.AllowNoChange = true
+		plan, err := EstimateSendUtxos(config)
+		require.NoError(t, err)
+
+		count, _ := changeOutputs(plan)
+		assert.Equal(t, 0, count)
+		assert.Equal(t, uint64(100000-5000), plan.Fee)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.Payments[0].Satoshis = 100000
+		config.ChangeSplit = &ChangeSplit{Outputs: 3}
+
+		_, err := SendUtxos(config)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+}
+
+// TestChangePolicy tests where change is sent and the limits on fees
+func TestChangePolicy(t *testing.T) {
+	t.Run("change derived from payment key", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.ChangeAddress = ""
+
+		tx, err := SendUtxos(config)
+		require.NoError(t, err)
+		require.Len(t, tx.Outputs, 2)
+
+		// Change goes back to the payment key's address
+		paymentAddr, err := script.NewAddressFromPublicKey(config.PaymentPk.PubKey(), true)
+		require.NoError(t, err)
+		changeAddr, err := tx.Outputs[1].LockingScript.Address()
+		require.NoError(t, err)
+		assert.Equal(t, paymentAddr.AddressString, changeAddr.AddressString)
+		assert.Greater(t, tx.Outputs[1].Satoshis, uint64(90000))
+	})
+
+	t.Run("no change address or key", func(t *testing.T) {
+		config := &CreateOrdinalsConfig{
+			Utxos: feeModelTestConfig(t).Utxos,
+			Destinations: []*Destination{{
+				Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+				Inscription: &inscription.Inscription{
+					File: inscription.File{
+						Content: []byte("Hello, world!"),
+						Type:    "text/plain",
+					},
+				},
+			}},
+		}
+
+		// A dry run without keys uses a placeholder change output
+		plan, err := EstimateCreateOrdinals(config)
+		require.NoError(t, err)
+		assert.Equal(t, TxRoleChange, plan.Outputs[1].Role)
+
+		// Allowing no change pays the leftover as fee
+		config.AllowNoChange = true
+		plan, err = EstimateCreateOrdinals(config)
+		require.NoError(t, err)
+		assert.Len(t, plan.Outputs, 1)
+		assert.Equal(t, uint64(100000-1), plan.Fee)
+	})
+
+	t.Run("max fee", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.FeeModel = &FixedFee{Satoshis: 500}
+		config.MaxFee = 499
+
+		_, err := SendUtxos(config)
+		assert.ErrorIs(t, err, ErrFeeTooHigh)
+
+		config.MaxFee = 500
+		_, err = SendUtxos(config)
+		assert.NoError(t, err)
+	})
+
+	t.Run("max fee applies without change", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.ChangeAddress = ""
+		config.PaymentPk = nil
+		config.AllowNoChange = true
+		config.MaxFee = 1000
+
+		_, err := EstimateSendUtxos(config)
+		assert.ErrorIs(t, err, ErrFeeTooHigh)
+	})
+
+	t.Run("missing change address", func(t *testing.T) {
+		config := feeModelTestConfig(t)
+		config.Utxos = nil
+		config.ChangeAddress = ""
+		config.PaymentPk = nil
+
+		_, err := SendUtxos(config)
+		assert.ErrorIs(t, err, ErrNoChangeAddress)
+	})
+}
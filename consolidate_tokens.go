@@ -0,0 +1,184 @@
+package ordinals
+
+import (
+	"fmt"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// ConsolidateTokenUtxosConfig represents configuration for merging many token UTXOs into fewer outputs
+type ConsolidateTokenUtxosConfig struct {
+	// Protocol is the token protocol (e.g., TokenTypeBSV21)
+	Protocol TokenType
+	// TokenID is the ID of the token to consolidate
+	TokenID string
+	// Utxos are the UTXOs used to pay for the first transaction
+	Utxos []*Utxo
+	// InputTokens are the candidate token UTXOs to consolidate
+	InputTokens []*TokenUtxo
+	// PaymentPk is the private key for the payment UTXOs
+	PaymentPk *ec.PrivateKey
+	// OrdPk is the private key for the token UTXOs; consolidated outputs are sent to its address
+	OrdPk *ec.PrivateKey
+	// ChangeAddress is the address to send the payment change of the last transaction to
+	// The change of earlier transactions funds the next one, so it always goes to the PaymentPk address.
+	ChangeAddress string
+	// SatsPerKb is the fee rate in satoshis per kilobyte
+	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
+	// Decimals is the number of decimal places for the token
+	Decimals uint8
+	// Amount is the amount of tokens to consolidate (in display format), zero consolidates every candidate
+	Amount float64
+	// SelectionOptions determines how candidates are chosen when Amount is set
+	SelectionOptions *TokenSelectionOptions
+	// MaxInputsPerTx is the maximum number of token inputs spent by one transaction
+	MaxInputsPerTx int
+	// MaxBytesPerTx is the maximum size of one transaction in bytes, DEFAULT_MAX_CONSOLIDATION_TX_BYTES if 0
+	// Batches above it are built again with fewer token inputs.
+	MaxBytesPerTx int
+	// SplitConfig sets the number and size of the consolidated outputs
+	SplitConfig *TokenSplitConfig
+}
+
+// ConsolidateTokenUtxos merges many token UTXOs into a target number of outputs
+// Candidates are chosen with SelectTokenUtxos. When more candidates are selected than
+// fit in one transaction, by MaxInputsPerTx or MaxBytesPerTx, the work is batched: each transaction merges its inputs into
+// a single output that is carried into the next one, along with the payment change.
+// The final transaction splits the merged tokens according to SplitConfig.
+// The returned transactions must be broadcast in order.
+func ConsolidateTokenUtxos(config *ConsolidateTokenUtxosConfig) ([]*transaction.Transaction, error) {
+	// Ensure input tokens match the expected tokenID
+	for _, token := range config.InputTokens {
+		if token.TokenID != config.TokenID {
+			return nil, fmt.Errorf("%w: input token %s doesn't match %s", ErrTokenIDMismatch, token.TokenID, config.TokenID)
+		}
+	}
+
+	if config.OrdPk == nil {
+		return nil, fmt.Errorf("ordPk is required to receive consolidated tokens")
+	}
+
+	maxInputs := config.MaxInputsPerTx
+	if maxInputs == 0 {
+		maxInputs = DEFAULT_MAX_TOKEN_INPUTS_PER_TX
+	}
+	if maxInputs < 2 {
+		return nil, fmt.Errorf("maxInputsPerTx must be at least 2")
+	}
+
+	maxBytes := config.MaxBytesPerTx
+	if maxBytes == 0 {
+		maxBytes = DEFAULT_MAX_CONSOLIDATION_TX_BYTES
+	}
+
+	// Choose the candidates to consolidate
+	selection := SelectTokenUtxos(config.InputTokens, config.Amount, config.Decimals, config.SelectionOptions)
+	if !selection.IsEnough || len(selection.SelectedUtxos) == 0 {
+		return nil, fmt.Errorf("not enough token UTXOs to consolidate: %w", ErrInsufficientTokens)
+	}
+
+	var txs []*transaction.Transaction
+	pending := selection.SelectedUtxos
+	payUtxos := config.Utxos
+	var carry *TokenUtxo
+
+	for len(pending) > 0 {
+		// Spend the tokens carried over from the previous batch first
+		var carried []*TokenUtxo
+		if carry != nil {
+			carried = append(carried, carry)
+		}
+
+		take := maxInputs - len(carried)
+		if take > len(pending) {
+			take = len(pending)
+		}
+
+		// Build the batch, with fewer token inputs while it is above the size limit
+		var inputs []*TokenUtxo
+		var tx *transaction.Transaction
+		var err error
+		for {
+			inputs = append(carried[:len(carried):len(carried)], pending[:take]...)
+			last := take == len(pending)
+
+			// Only the final batch applies the target split
+			var splitConfig *TokenSplitConfig
+			if last {
+				splitConfig = config.SplitConfig
+			}
+
+			tx, err = TransferOrdTokens(&TransferBsv21TokenConfig{
+				Protocol:       config.Protocol,
+				TokenID:        config.TokenID,
+				Utxos:          payUtxos,
+				InputTokens:    inputs,
+				PaymentPk:      config.PaymentPk,
+				OrdPk:          config.OrdPk,
+				ChangeAddress:  chainedChangeAddress(config.ChangeAddress, last),
+				SatsPerKb:      config.SatsPerKb,
+				BuildOptions:   config.BuildOptions,
+				TokenInputMode: TokenInputModeAll,
+				SplitConfig:    splitConfig,
+				Decimals:       config.Decimals,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to build consolidation batch %d: %w", len(txs), err)
+			}
+
+			size := tx.Size()
+			if size <= maxBytes {
+				break
+			}
+			if take == 1 {
+				return nil, fmt.Errorf("consolidation batch %d is %d bytes with a single token input, above the limit of %d", len(txs), size, maxBytes)
+			}
+
+			// Shrink in proportion to the excess, by at least one input
+			smaller := take * maxBytes / size
+			if smaller >= take {
+				smaller = take - 1
+			}
+			if smaller < 1 {
+				smaller = 1
+			}
+			take = smaller
+		}
+		pending = pending[take:]
+		txs = append(txs, tx)
+
+		if len(pending) == 0 {
+			break
+		}
+
+		// Carry the merged tokens and payment change into the next batch
+		var merged uint64
+		for _, tokenUtxo := range inputs {
+			merged += tokenUtxo.Amount
+		}
+
+		// With no distributions the merged token output comes first
+		carry = &TokenUtxo{
+			Utxo: Utxo{
+				TxID:         tx.TxID().String(),
+				Vout:         0,
+				ScriptPubKey: tx.Outputs[0].LockingScript.String(),
+				Satoshis:     tx.Outputs[0].Satoshis,
+			},
+			TokenID:  config.TokenID,
+			Protocol: config.Protocol,
+			Amount:   merged,
+			Decimals: config.Decimals,
+		}
+
+		payUtxos = ChangeUtxos(tx)
+		if len(payUtxos) == 0 {
+			return nil, fmt.Errorf("consolidation batch %d left no payment change to fund the next batch", len(txs)-1)
+		}
+	}
+
+	return txs, nil
+}
@@ -0,0 +1,165 @@
+package ordinals
+
+import (
+	"fmt"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidateTokenUtxos(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	// Create many small token UTXOs
+	tokenUtxos := make([]*TokenUtxo, 0, 25)
+	for i := 0; i < 25; i++ {
+		tokenUtxos = append(tokenUtxos, &TokenUtxo{
+			Utxo: Utxo{
+				TxID:         fmt.Sprintf("%064x", i+1),
+				Vout:         0,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     1,
+			},
+			TokenID:  tokenID,
+			Protocol: TokenTypeBSV21,
+			Amount:   uint64(i + 1),
+		})
+	}
+
+	paymentUtxo := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000fff",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}
+
+	baseCfg := &ConsolidateTokenUtxosConfig{
+		Protocol:      TokenTypeBSV21,
+		TokenID:       tokenID,
+		Utxos:         []*Utxo{paymentUtxo},
+		InputTokens:   tokenUtxos,
+		PaymentPk:     paymentPk,
+		OrdPk:         ordPk,
+		ChangeAddress: "1BitcoinEaterAddressDontSendf59kuE",
+	}
+
+	t.Run("single transaction", func(t *testing.T) {
+		txs, err := ConsolidateTokenUtxos(baseCfg)
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+
+		// 25 token inputs and 1 payment input merged into 1 token output plus change
+		assert.Equal(t, 26, len(txs[0].Inputs))
+		assert.Equal(t, 2, len(txs[0].Outputs))
+	})
+
+	t.Run("batched with target split", func(t *testing.T) {
+		cfg := *baseCfg
+		cfg.MaxInputsPerTx = 10
+		cfg.SplitConfig = &TokenSplitConfig{Outputs: 3}
+
+		txs, err := ConsolidateTokenUtxos(&cfg)
+		require.NoError(t, err)
+
+		// 10 inputs, then carry + 9, then carry + 6
+		require.Len(t, txs, 3)
+
+		for i := 1; i < len(txs); i++ {
+			prevID := txs[i-1].TxID().String()
+			assert.Equal(t, prevID, txs[i].Inputs[0].SourceTXID.String(), "first input should spend the carried tokens")
+			assert.Equal(t, uint32(0), txs[i].Inputs[0].SourceTxOutIndex)
+		}
+
+		// Final transaction: 3 token outputs plus payment change
+		assert.Equal(t, 4, len(txs[2].Outputs))
+	})
+
+	t.Run("batched by size", func(t *testing.T) {
+		cfg := *baseCfg
+		cfg.MaxBytesPerTx = 2000
+
+		txs, err := ConsolidateTokenUtxos(&cfg)
+		require.NoError(t, err)
+		require.Greater(t, len(txs), 1)
+
+		inputs := 0
+		for i, tx := range txs {
+			assert.LessOrEqual(t, tx.Size(), 2000, "transaction %d", i)
+			inputs += len(tx.Inputs) - 1
+			if i > 0 {
+				inputs-- // the carried tokens
+			}
+		}
+		assert.Equal(t, 25, inputs)
+
+		cfg.MaxBytesPerTx = 100
+		_, err = ConsolidateTokenUtxos(&cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("change address only receives the last change", func(t *testing.T) {
+		ordAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+		require.NoError(t, err)
+		ordScript, err := p2pkh.Lock(ordAddr)
+		require.NoError(t, err)
+
+		// Token UTXOs the ord key can really spend
+		owned := make([]*TokenUtxo, 0, len(tokenUtxos))
+		for _, tokenUtxo := range tokenUtxos {
+			ownedUtxo := *tokenUtxo
+			ownedUtxo.ScriptPubKey = ordScript.String()
+			owned = append(owned, &ownedUtxo)
+		}
+
+		cfg := *baseCfg
+		cfg.InputTokens = owned
+		cfg.MaxInputsPerTx = 10
+
+		txs, err := ConsolidateTokenUtxos(&cfg)
+		require.NoError(t, err)
+		require.Len(t, txs, 3)
+
+		// Later transactions spend the payment change with the payment key
+		verifyInputs(t, txs[1])
+		verifyInputs(t, txs[2])
+
+		changeScript, err := script.NewAddressFromString(cfg.ChangeAddress)
+		require.NoError(t, err)
+		lastChange := ChangeUtxos(txs[2])
+		require.Len(t, lastChange, 1)
+		assert.Contains(t, lastChange[0].ScriptPubKey, fmt.Sprintf("%x", changeScript.PublicKeyHash))
+	})
+
+	t.Run("select only what is needed", func(t *testing.T) {
+		cfg := *baseCfg
+		cfg.Amount = 20
+		cfg.SelectionOptions = &TokenSelectionOptions{
+			InputStrategy: TokenSelectionStrategyLargestFirst,
+		}
+
+		txs, err := ConsolidateTokenUtxos(&cfg)
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+
+		// 25 alone covers 20, plus the payment input
+		assert.Equal(t, 2, len(txs[0].Inputs))
+	})
+
+	t.Run("mismatched token id", func(t *testing.T) {
+		cfg := *baseCfg
+		cfg.TokenID = "other_0"
+
+		txs, err := ConsolidateTokenUtxos(&cfg)
+		assert.Error(t, err)
+		assert.Nil(t, txs)
+	})
+}
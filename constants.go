@@ -6,5 +6,39 @@ const DEFAULT_SAT_PER_KB uint64 = 10
 // API_HOST is the default 1Sat Ordinals API host
 const API_HOST = "https://ordinals.gorillapool.io/api"
 
+// MINER_POLICY_URL is the default ARC policy endpoint used to fetch the mining fee
+const MINER_POLICY_URL = "https://arc.gorillapool.io/v1/policy"
+
 // MAP_PREFIX is the standard MAP prefix
 const MAP_PREFIX = "1PuQa7K62MiKCtssSLKy1kh56WWU7MtUR5"
+
+// DEFAULT_AIRDROP_RECIPIENTS_PER_TX is the default number of recipients paid in each airdrop transaction
+const DEFAULT_AIRDROP_RECIPIENTS_PER_TX = 100
+
+// DEFAULT_MAX_AIRDROP_TX_BYTES is the default size limit in bytes of each airdrop transaction
+const DEFAULT_MAX_AIRDROP_TX_BYTES = 100000
+
+// DEFAULT_MAX_TOKEN_INPUTS_PER_TX is the default number of token inputs spent by each consolidation transaction
+const DEFAULT_MAX_TOKEN_INPUTS_PER_TX = 100
+
+// P2PKH_UNLOCKING_SCRIPT_SIZE is the estimated size of a P2PKH signature and compressed public key
+const P2PKH_UNLOCKING_SCRIPT_SIZE = 106
+
+// DEFAULT_MAX_NO_CHANGE_FEE is the highest fee paid by a transaction without a change output
+// It guards against leftover satoshis being donated to miners, see BuildOptions.MaxFee.
+const DEFAULT_MAX_NO_CHANGE_FEE uint64 = 10000
+
+// DUST_LIMIT is the smallest number of satoshis a spendable output may hold
+const DUST_LIMIT uint64 = 1
+
+// DEFAULT_BATCH_MAX_OUTPUTS is the default number of inscriptions created by each BatchInscribe transaction
+const DEFAULT_BATCH_MAX_OUTPUTS = 100
+
+// DEFAULT_BATCH_MAX_BYTES is the default total size of the inscription scripts in each BatchInscribe transaction
+const DEFAULT_BATCH_MAX_BYTES = 5000000
+
+// DEFAULT_MAX_INSCRIPTION_SIZE is the default size limit in bytes of a file inscribed from a File, reader or path
+const DEFAULT_MAX_INSCRIPTION_SIZE int64 = 10000000
+
+// DEFAULT_MAX_CONSOLIDATION_TX_BYTES is the default size limit in bytes of each consolidation transaction
+const DEFAULT_MAX_CONSOLIDATION_TX_BYTES = 100000
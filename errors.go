@@ -0,0 +1,92 @@
+package ordinals
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// Sentinel errors returned by the transaction builders
+// Match them with errors.Is, builders wrap them with context about the operation
+var (
+	// ErrInsufficientFunds is returned when the payment UTXOs can't cover the outputs and fee
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrInsufficientTokens is returned when the token UTXOs can't cover the requested token amount
+	ErrInsufficientTokens = errors.New("insufficient tokens")
+	// ErrInvalidAddress is returned when an address can't be parsed
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrOrdinalNotOneSat is returned when an ordinal UTXO doesn't hold exactly 1 satoshi
+	ErrOrdinalNotOneSat = errors.New("1Sat Ordinal utxos must have exactly 1 satoshi")
+	// ErrUnsupportedProtocol is returned when a token protocol isn't supported by the operation
+	ErrUnsupportedProtocol = errors.New("unsupported token protocol")
+	// ErrFeeTooHigh is returned when a fee is above the configured limit
+	ErrFeeTooHigh = errors.New("fee too high")
+	// ErrNoChangeAddress is returned when change can't be sent anywhere, set ChangeAddress, PaymentPk or AllowNoChange
+	ErrNoChangeAddress = errors.New("either changeAddress or paymentPk is required")
+	// ErrConservation is returned when a transaction would lose satoshis, ordinals or tokens
+	ErrConservation = errors.New("conservation rules violated")
+	// ErrValidation is returned by Validate when a config has problems, see ValidationError
+	ErrValidation = errors.New("invalid config")
+	// ErrMissingKey is returned when a private key needed to sign an input isn't set
+	ErrMissingKey = errors.New("missing private key")
+	// ErrDuplicateOutpoint is returned when the same outpoint is spent more than once
+	ErrDuplicateOutpoint = errors.New("duplicate outpoint")
+	// ErrOrdinalPayment is returned when a payment UTXO holds an inscription, token or listing
+	ErrOrdinalPayment = errors.New("payment utxo holds an ordinal")
+	// ErrTokenIDMismatch is returned when a token UTXO doesn't belong to the token being spent
+	ErrTokenIDMismatch = errors.New("token ID mismatch")
+	// ErrInvalidToken is returned when a token UTXO isn't a valid, unspent output of its token
+	ErrInvalidToken = errors.New("invalid token utxo")
+	// ErrDustOutput is returned when an output pays less than DUST_LIMIT satoshis
+	ErrDustOutput = errors.New("dust output")
+	// ErrUtxoUnavailable is returned when a Wallet UTXO doesn't exist or is already reserved
+	ErrUtxoUnavailable = errors.New("utxo unavailable")
+	// ErrEmptyContent is returned when a file to inscribe has no content
+	ErrEmptyContent = errors.New("empty content")
+	// ErrContentTooLarge is returned when a file to inscribe is above the size limit
+	ErrContentTooLarge = errors.New("content too large")
+)
+
+// InsufficientFundsError reports how many satoshis a transaction needed and how many were available
+// It matches ErrInsufficientFunds with errors.Is
+type InsufficientFundsError struct {
+	// Needed is the number of satoshis required for the outputs and fee
+	Needed uint64
+	// Available is the number of satoshis in the inputs
+	Available uint64
+}
+
+// Error implements the error interface
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%s: need %d satoshis, have %d", ErrInsufficientFunds, e.Needed, e.Available)
+}
+
+// Is reports whether target is ErrInsufficientFunds
+func (e *InsufficientFundsError) Is(target error) bool {
+	return target == ErrInsufficientFunds
+}
+
+// Unwrap returns the underlying go-sdk error so existing checks keep working
+func (e *InsufficientFundsError) Unwrap() error {
+	return transaction.ErrInsufficientInputs
+}
+
+// InsufficientTokensError reports how many tokens an operation needed and how many were available
+// Amounts are raw token amounts. It matches ErrInsufficientTokens with errors.Is
+type InsufficientTokensError struct {
+	// Needed is the number of tokens required
+	Needed uint64
+	// Available is the number of tokens in the token inputs
+	Available uint64
+}
+
+// Error implements the error interface
+func (e *InsufficientTokensError) Error() string {
+	return fmt.Sprintf("%s: need %d, have %d", ErrInsufficientTokens, e.Needed, e.Available)
+}
+
+// Is reports whether target is ErrInsufficientTokens
+func (e *InsufficientTokensError) Is(target error) bool {
+	return target == ErrInsufficientTokens
+}
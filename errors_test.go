@@ -0,0 +1,153 @@
+package ordinals
+
+import (
+	"errors"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuilderErrors tests that builders wrap the exported sentinel errors
+func TestBuilderErrors(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentUtxo := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     1000,
+	}
+
+	tokenUtxo := &TokenUtxo{
+		Utxo: Utxo{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     1,
+		},
+		TokenID:  tokenID,
+		Protocol: TokenTypeBSV21,
+		Amount:   100,
+	}
+
+	transferConfig := func() *TransferBsv21TokenConfig {
+		return &TransferBsv21TokenConfig{
+			Protocol:    TokenTypeBSV21,
+			TokenID:     tokenID,
+			Utxos:       []*Utxo{paymentUtxo},
+			InputTokens: []*TokenUtxo{tokenUtxo},
+			Distributions: []*TokenDistribution{
+				{
+					Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+					Tokens:  50,
+				},
+			},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		}
+	}
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		_, err := SendUtxos(&SendUtxosConfig{
+			Utxos:     []*Utxo{paymentUtxo},
+			PaymentPk: paymentPk,
+			Payments: []*PayToAddress{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 5000},
+			},
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+		assert.ErrorIs(t, err, transaction.ErrInsufficientInputs)
+
+		var fundsErr *InsufficientFundsError
+		require.ErrorAs(t, err, &fundsErr)
+		assert.Equal(t, uint64(1000), fundsErr.Available)
+		assert.Greater(t, fundsErr.Needed, uint64(5000))
+	})
+
+	t.Run("insufficient tokens", func(t *testing.T) {
+		cfg := transferConfig()
+		cfg.Distributions[0].Tokens = 150
+
+		_, err := TransferOrdTokens(cfg)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInsufficientTokens)
+
+		var tokensErr *InsufficientTokensError
+		require.ErrorAs(t, err, &tokensErr)
+		assert.Equal(t, uint64(150), tokensErr.Needed)
+		assert.Equal(t, uint64(100), tokensErr.Available)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		cfg := transferConfig()
+		cfg.Distributions[0].Address = "not-an-address"
+
+		_, err := TransferOrdTokens(cfg)
+		assert.ErrorIs(t, err, ErrInvalidAddress)
+	})
+
+	t.Run("unsupported protocol", func(t *testing.T) {
+		cfg := transferConfig()
+		cfg.Protocol = TokenType("bsv-20")
+
+		_, err := TransferOrdTokens(cfg)
+		assert.ErrorIs(t, err, ErrUnsupportedProtocol)
+	})
+
+	t.Run("token ID mismatch", func(t *testing.T) {
+		cfg := transferConfig()
+		cfg.TokenID = "0000000000000000000000000000000000000000000000000000000000000005_0"
+
+		_, err := TransferOrdTokens(cfg)
+		assert.ErrorIs(t, err, ErrTokenIDMismatch)
+
+		_, err = ConsolidateTokenUtxos(&ConsolidateTokenUtxosConfig{
+			TokenID:     cfg.TokenID,
+			InputTokens: cfg.InputTokens,
+			Utxos:       cfg.Utxos,
+			PaymentPk:   paymentPk,
+			OrdPk:       ordPk,
+		})
+		assert.ErrorIs(t, err, ErrTokenIDMismatch)
+	})
+
+	t.Run("ordinal not one sat", func(t *testing.T) {
+		_, err := SendOrdinals(&SendOrdinalsConfig{
+			PaymentUtxos: []*Utxo{paymentUtxo},
+			Ordinals: []*NftUtxo{{
+				Utxo: Utxo{
+					TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+					Vout:         0,
+					ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+					Satoshis:     2,
+				},
+			}},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+			Destinations: []*Destination{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"},
+			},
+		})
+		assert.ErrorIs(t, err, ErrOrdinalNotOneSat)
+	})
+
+	t.Run("wrapped sentinels stay distinct", func(t *testing.T) {
+		err := &InsufficientFundsError{Needed: 10, Available: 5}
+		assert.True(t, errors.Is(err, ErrInsufficientFunds))
+		assert.False(t, errors.Is(err, ErrInsufficientTokens))
+		assert.Equal(t, "insufficient funds: need 10 satoshis, have 5", err.Error())
+	})
+}
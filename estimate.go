@@ -0,0 +1,145 @@
+package ordinals
+
+// Estimate functions build the same transaction as their builder without signing it
+// Private keys are not required. Inputs are sized as signed P2PKH inputs, so the
+// fee, size and change in the returned TxPlan match the signed transaction.
+
+// EstimateCreateOrdinals plans the transaction built by CreateOrdinals
+func EstimateCreateOrdinals(config *CreateOrdinalsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildCreateOrdinals(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateSendOrdinals plans the transaction built by SendOrdinals
+func EstimateSendOrdinals(config *SendOrdinalsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildSendOrdinals(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateSendUtxos plans the transaction built by SendUtxos
+func EstimateSendUtxos(config *SendUtxosConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildSendUtxos(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateDeployBsv21Token plans the transaction built by DeployBsv21Token
+func EstimateDeployBsv21Token(config *DeployBsv21TokenConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildDeployBsv21Token(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateTransferOrdTokens plans the transaction built by TransferOrdTokens
+func EstimateTransferOrdTokens(config *TransferBsv21TokenConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if _, _, err := buildTransferOrdTokens(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateBurnOrdTokens plans the transaction built by BurnOrdTokens
+func EstimateBurnOrdTokens(config *BurnOrdTokensConfig) (*TxPlan, error) {
+	transferConfig, err := burnTransferConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return EstimateTransferOrdTokens(transferConfig)
+}
+
+// EstimateBurnOrdinals plans the transaction built by BurnOrdinals
+func EstimateBurnOrdinals(config *BurnOrdinalsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildBurnOrdinals(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateCreateOrdListings plans the transaction built by CreateOrdListings
+func EstimateCreateOrdListings(config *CreateOrdListingsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildCreateOrdListings(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimatePurchaseOrdListing plans the transaction built by PurchaseOrdListing
+func EstimatePurchaseOrdListing(config *PurchaseOrdListingConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildPurchaseOrdListing(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateCancelOrdListings plans the transaction built by CancelOrdListings
+func EstimateCancelOrdListings(config *CancelOrdListingsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildCancelOrdListings(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateCreateOrdTokenListings plans the transaction built by CreateOrdTokenListings
+func EstimateCreateOrdTokenListings(config *CreateOrdTokenListingsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildCreateOrdTokenListings(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimatePurchaseOrdTokenListing plans the transaction built by PurchaseOrdTokenListing
+func EstimatePurchaseOrdTokenListing(config *PurchaseOrdTokenListingConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildPurchaseOrdTokenListing(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateCancelOrdTokenListings plans the transaction built by CancelOrdTokenListings
+func EstimateCancelOrdTokenListings(config *CancelOrdTokenListingsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildCancelOrdTokenListings(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
+
+// EstimateOperations plans the transaction built by BuildOperations
+func EstimateOperations(config *OperationsConfig) (*TxPlan, error) {
+	b := newTxBuilder(true)
+	if err := buildOperations(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.Plan(), nil
+}
@@ -0,0 +1,155 @@
+package ordinals
+
+import (
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txFee returns the fee paid by a built transaction
+func txFee(t *testing.T, plan *TxPlan) uint64 {
+	totalIn, err := plan.Tx.TotalInputSatoshis()
+	require.NoError(t, err)
+	return totalIn - plan.Tx.TotalOutputSatoshis()
+}
+
+// TestEstimateCreateOrdinals tests that an estimate matches the signed transaction
+func TestEstimateCreateOrdinals(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	config := &CreateOrdinalsConfig{
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     100000,
+		}},
+		Destinations: []*Destination{{
+			Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+			Inscription: &inscription.Inscription{
+				File: inscription.File{
+					Content: []byte("Hello, world!"),
+					Type:    "text/plain",
+				},
+			},
+		}},
+		PaymentPk:     paymentPk,
+		ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		SatsPerKb:     50,
+	}
+
+	plan, err := EstimateCreateOrdinals(config)
+	require.NoError(t, err)
+
+	tx, err := CreateOrdinals(config)
+	require.NoError(t, err)
+
+	// The estimate is unsigned and spends the same inputs to the same outputs
+	assert.Nil(t, plan.Tx.Inputs[0].UnlockingScript)
+	assert.Equal(t, len(tx.Inputs), len(plan.Inputs))
+	assert.Equal(t, len(tx.Outputs), len(plan.Outputs))
+	for i, output := range tx.Outputs {
+		assert.Equal(t, output.Satoshis, plan.Outputs[i].Satoshis)
+		assert.Equal(t, output.LockingScript.String(), plan.Outputs[i].LockingScript.String())
+	}
+
+	// Fee and change match the signed transaction
+	assert.Equal(t, txFee(t, plan), plan.Fee)
+	assert.Equal(t, tx.Outputs[1].Satoshis, plan.Change)
+	assert.Equal(t, uint64(100000)-1-plan.Change, plan.Fee)
+
+	// Size is within a couple of bytes of the signed transaction, signatures vary in length
+	assert.InDelta(t, float64(tx.Size()), float64(plan.Size), 2)
+
+	// Roles describe the layout
+	assert.Equal(t, TxRolePayment, plan.Inputs[0].Role)
+	assert.Equal(t, TxRoleInscription, plan.Outputs[0].Role)
+	assert.Equal(t, TxRoleChange, plan.Outputs[1].Role)
+}
+
+// TestEstimateWithoutKeys tests that estimates don't need private keys
+func TestEstimateWithoutKeys(t *testing.T) {
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentUtxo := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}
+
+	t.Run("send utxos", func(t *testing.T) {
+		plan, err := EstimateSendUtxos(&SendUtxosConfig{
+			Utxos: []*Utxo{paymentUtxo},
+			Payments: []*PayToAddress{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 5000},
+			},
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, len(plan.Inputs))
+		assert.Equal(t, TxRolePayment, plan.Outputs[0].Role)
+		assert.Equal(t, TxRoleChange, plan.Outputs[1].Role)
+		assert.Equal(t, uint64(100000-5000)-plan.Fee, plan.Change)
+		assert.Greater(t, plan.Fee, uint64(0))
+	})
+
+	t.Run("transfer tokens", func(t *testing.T) {
+		plan, err := EstimateTransferOrdTokens(&TransferBsv21TokenConfig{
+			Protocol: TokenTypeBSV21,
+			TokenID:  tokenID,
+			Utxos:    []*Utxo{paymentUtxo},
+			InputTokens: []*TokenUtxo{{
+				Utxo: Utxo{
+					TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+					Vout:         0,
+					ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+					Satoshis:     1,
+				},
+				TokenID:  tokenID,
+				Protocol: TokenTypeBSV21,
+				Amount:   1000,
+			}},
+			Distributions: []*TokenDistribution{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Tokens: 400},
+			},
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		})
+		require.NoError(t, err)
+
+		roles := make([]TxRole, 0, len(plan.Outputs))
+		for _, output := range plan.Outputs {
+			roles = append(roles, output.Role)
+		}
+		assert.Equal(t, []TxRole{TxRoleToken, TxRoleTokenChange, TxRoleChange}, roles)
+		assert.Equal(t, TxRoleToken, plan.Inputs[0].Role)
+		assert.Equal(t, TxRolePayment, plan.Inputs[1].Role)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		_, err := EstimateSendUtxos(&SendUtxosConfig{
+			Utxos: []*Utxo{paymentUtxo},
+			Payments: []*PayToAddress{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 200000},
+			},
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		})
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("builders still require keys", func(t *testing.T) {
+		_, err := SendUtxos(&SendUtxosConfig{
+			Utxos: []*Utxo{paymentUtxo},
+			Payments: []*PayToAddress{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 5000},
+			},
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		})
+		assert.Error(t, err)
+	})
+}
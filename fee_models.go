@@ -0,0 +1,268 @@
+package ordinals
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+)
+
+// BuildOptions are options shared by every transaction builder
+// It is embedded in the builder configs.
+type BuildOptions struct {
+	// FeeModel calculates the transaction fee, overriding SatsPerKb when set
+	FeeModel transaction.FeeModel
+	// ChangeSplit splits the payment change into several outputs, a single change output is created if nil
+	ChangeSplit *ChangeSplit
+	// MaxFee is the highest fee in satoshis any transaction may pay, 0 means no limit
+	// Transactions without a change output are limited to DEFAULT_MAX_NO_CHANGE_FEE when it is 0.
+	MaxFee uint64
+	// AllowNoChange builds without a change output when neither ChangeAddress nor PaymentPk is set
+	// The leftover satoshis are paid to the miner, only MaxFee limits the fee.
+	AllowNoChange bool
+	// PaymentScreen opts in to spending payment UTXOs that may hold an ordinal, they are rejected by default
+	PaymentScreen PaymentScreen
+	// Logger receives debug logs of input and output decisions and the fee calculation, nothing is logged if nil
+	Logger *slog.Logger
+}
+
+// feeModel returns the configured fee model, falling back to satsPerKb or DEFAULT_SAT_PER_KB
+func (o BuildOptions) feeModel(satsPerKb uint64) transaction.FeeModel {
+	if o.FeeModel != nil {
+		return o.FeeModel
+	}
+
+	if satsPerKb == 0 {
+		satsPerKb = DEFAULT_SAT_PER_KB
+	}
+
+	return &feemodel.SatoshisPerKilobyte{
+		Satoshis: satsPerKb,
+	}
+}
+
+// SatoshisPerByte charges a possibly fractional number of satoshis per byte
+// The fee is rounded up to the next whole satoshi.
+type SatoshisPerByte struct {
+	// Rate is the fee rate in satoshis per byte, e.g. 0.05
+	Rate float64
+}
+
+// ComputeFee implements transaction.FeeModel
+func (s *SatoshisPerByte) ComputeFee(tx *transaction.Transaction) (uint64, error) {
+	if s.Rate < 0 || math.IsNaN(s.Rate) || math.IsInf(s.Rate, 0) {
+		return 0, fmt.Errorf("invalid fee rate: %v satoshis per byte", s.Rate)
+	}
+
+	return uint64(math.Ceil(float64(estimateTxSize(tx)) * s.Rate)), nil
+}
+
+// FixedFee charges the same fee regardless of transaction size
+type FixedFee struct {
+	// Satoshis is the fee paid by every transaction
+	Satoshis uint64
+}
+
+// ComputeFee implements transaction.FeeModel
+func (f *FixedFee) ComputeFee(_ *transaction.Transaction) (uint64, error) {
+	return f.Satoshis, nil
+}
+
+// CappedFee refuses to build a transaction when the wrapped fee model asks for more than Max
+// Use it as a safety limit around dynamic fee rates.
+type CappedFee struct {
+	// Model is the fee model that calculates the fee
+	Model transaction.FeeModel
+	// Max is the highest fee in satoshis that will be paid
+	Max uint64
+}
+
+// ComputeFee implements transaction.FeeModel
+func (c *CappedFee) ComputeFee(tx *transaction.Transaction) (uint64, error) {
+	if c.Model == nil {
+		return 0, fmt.Errorf("capped fee requires a fee model")
+	}
+
+	fee, err := c.Model.ComputeFee(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	if fee > c.Max {
+		return 0, fmt.Errorf("%w: %d satoshis is above the limit of %d", ErrFeeTooHigh, fee, c.Max)
+	}
+
+	return fee, nil
+}
+
+// FeeRate is a fee rate expressed as Satoshis per number of Bytes
+type FeeRate struct {
+	// Satoshis charged per Bytes
+	Satoshis uint64
+	// Bytes the Satoshis are charged for
+	Bytes uint64
+}
+
+// FeeRateProvider supplies the current fee rate, e.g. from a miner policy endpoint
+type FeeRateProvider interface {
+	// FeeRate returns the current fee rate
+	FeeRate() (*FeeRate, error)
+}
+
+// DynamicFee fetches the fee rate from a provider
+// Each build fetches the rate once and uses it for every fee calculation of the transaction,
+// calling ComputeFee directly fetches it every time.
+type DynamicFee struct {
+	// Provider supplies the fee rate
+	Provider FeeRateProvider
+	// Fallback is used when the provider fails, the error is returned if it is nil
+	Fallback transaction.FeeModel
+}
+
+// ComputeFee implements transaction.FeeModel
+func (d *DynamicFee) ComputeFee(tx *transaction.Transaction) (uint64, error) {
+	feeModel, err := d.resolve()
+	if err != nil {
+		return 0, err
+	}
+
+	return feeModel.ComputeFee(tx)
+}
+
+// resolve fetches the rate and returns a fee model charging it, or the fallback if the provider fails
+func (d *DynamicFee) resolve() (transaction.FeeModel, error) {
+	rate, err := d.fetchRate()
+	if err != nil {
+		if d.Fallback != nil {
+			return d.Fallback, nil
+		}
+		return nil, err
+	}
+
+	return &feeRateModel{rate: rate}, nil
+}
+
+// fetchRate fetches and validates the rate from the provider
+func (d *DynamicFee) fetchRate() (*FeeRate, error) {
+	if d.Provider == nil {
+		return nil, fmt.Errorf("dynamic fee requires a fee rate provider")
+	}
+
+	rate, err := d.Provider.FeeRate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee rate: %w", err)
+	}
+
+	if rate == nil || rate.Bytes == 0 {
+		return nil, fmt.Errorf("fee rate provider returned an invalid rate")
+	}
+
+	return rate, nil
+}
+
+// feeRateModel charges a fetched fee rate, rounded up to the next whole satoshi
+type feeRateModel struct {
+	rate *FeeRate
+}
+
+// ComputeFee implements transaction.FeeModel
+func (f *feeRateModel) ComputeFee(tx *transaction.Transaction) (uint64, error) {
+	size := estimateTxSize(tx)
+	return (size*f.rate.Satoshis + f.rate.Bytes - 1) / f.rate.Bytes, nil
+}
+
+// resolveFeeModel fetches the rate of a DynamicFee, also inside a CappedFee, once for a build
+// The fee is computed several times while the change is distributed, the provider is asked only once.
+func resolveFeeModel(feeModel transaction.FeeModel) (transaction.FeeModel, error) {
+	switch m := feeModel.(type) {
+	case *DynamicFee:
+		return m.resolve()
+	case *CappedFee:
+		model, err := resolveFeeModel(m.Model)
+		if err != nil {
+			return nil, err
+		}
+		return &CappedFee{Model: model, Max: m.Max}, nil
+	}
+
+	return feeModel, nil
+}
+
+// MinerPolicyFeeRateProvider fetches the mining fee from an ARC policy endpoint
+type MinerPolicyFeeRateProvider struct {
+	// URL is the policy endpoint, defaults to MINER_POLICY_URL
+	URL string
+	// APIKey is sent as a bearer token if set
+	APIKey string
+	// Client is the HTTP client to use, defaults to a client with a 10 second timeout
+	Client *http.Client
+	// Logger receives a debug log of the policy request, nothing is logged if nil
+	Logger *slog.Logger
+}
+
+// minerPolicyResponse represents the response of an ARC policy endpoint
+type minerPolicyResponse struct {
+	Policy struct {
+		MiningFee struct {
+			Satoshis uint64 `json:"satoshis"`
+			Bytes    uint64 `json:"bytes"`
+		} `json:"miningFee"`
+	} `json:"policy"`
+}
+
+// FeeRate implements FeeRateProvider
+func (p *MinerPolicyFeeRateProvider) FeeRate() (*FeeRate, error) {
+	url := p.URL
+	if url == "" {
+		url = MINER_POLICY_URL
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy request: %w", err)
+	}
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		loggerOrDiscard(p.Logger).Debug("http request failed", "method", req.Method, "url", url, "duration", time.Since(start), "error", err)
+		return nil, fmt.Errorf("failed to fetch miner policy: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	loggerOrDiscard(p.Logger).Debug("http request", "method", req.Method, "url", url, "status", resp.StatusCode, "bytes", len(body), "duration", time.Since(start))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("miner policy request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var policy minerPolicyResponse
+	if err := json.Unmarshal(body, &policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &FeeRate{
+		Satoshis: policy.Policy.MiningFee.Satoshis,
+		Bytes:    policy.Policy.MiningFee.Bytes,
+	}, nil
+}
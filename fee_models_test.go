@@ -0,0 +1,200 @@
+package ordinals
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFeeRateProvider returns a fixed rate or error
+type stubFeeRateProvider struct {
+	rate  *FeeRate
+	err   error
+	calls int
+}
+
+// FeeRate implements FeeRateProvider
+func (s *stubFeeRateProvider) FeeRate() (*FeeRate, error) {
+	s.calls++
+	return s.rate, s.err
+}
+
+// feeModelTestConfig returns a SendUtxos config paying 5000 satoshis from a 100000 satoshi UTXO
+func feeModelTestConfig(t *testing.T) *SendUtxosConfig {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &SendUtxosConfig{
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     100000,
+		}},
+		PaymentPk: paymentPk,
+		Payments: []*PayToAddress{
+			{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 5000},
+		},
+		ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+	}
+}
+
+// TestFeeModels tests the built-in fee models through a real builder
+func TestFeeModels(t *testing.T) {
+	// A 1 input, 2 output P2PKH transaction is estimated at 225 bytes
+	const txSize = 225
+
+	tests := []struct {
+		name        string
+		feeModel    transaction.FeeModel
+		expectedFee uint64
+		expectedErr error
+	}{
+		{
+			name:        "default sats per kb",
+			feeModel:    nil,
+			expectedFee: DEFAULT_SAT_PER_KB,
+		},
+		{
+			name:        "fractional sats per byte",
+			feeModel:    &SatoshisPerByte{Rate: 0.5},
+			expectedFee: (txSize + 1) / 2,
+		},
+		{
+			name:        "fractional sats per byte rounds up",
+			feeModel:    &SatoshisPerByte{Rate: 0.01},
+			expectedFee: 3,
+		},
+		{
+			name:        "fixed fee",
+			feeModel:    &FixedFee{Satoshis: 42},
+			expectedFee: 42,
+		},
+		{
+			name:        "capped fee below limit",
+			feeModel:    &CappedFee{Model: &FixedFee{Satoshis: 42}, Max: 100},
+			expectedFee: 42,
+		},
+		{
+			name:        "capped fee above limit",
+			feeModel:    &CappedFee{Model: &SatoshisPerByte{Rate: 10}, Max: 1000},
+			expectedErr: ErrFeeTooHigh,
+		},
+		{
+			name:        "dynamic fee",
+			feeModel:    &DynamicFee{Provider: &stubFeeRateProvider{rate: &FeeRate{Satoshis: 1, Bytes: 10}}},
+			expectedFee: 23,
+		},
+		{
+			name: "dynamic fee falls back",
+			feeModel: &DynamicFee{
+				Provider: &stubFeeRateProvider{err: fmt.Errorf("unavailable")},
+				Fallback: &FixedFee{Satoshis: 7},
+			},
+			expectedFee: 7,
+		},
+		{
+			name:        "dynamic fee without fallback",
+			feeModel:    &DynamicFee{Provider: &stubFeeRateProvider{err: fmt.Errorf("unavailable")}},
+			expectedErr: fmt.Errorf("unavailable"),
+		},
+		{
+			name:        "dynamic fee with invalid rate",
+			feeModel:    &DynamicFee{Provider: &stubFeeRateProvider{rate: &FeeRate{Satoshis: 1}}},
+			expectedErr: fmt.Errorf("invalid rate"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := feeModelTestConfig(t)
+			config.FeeModel = tt.feeModel
+
+			plan, err := EstimateSendUtxos(config)
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				if tt.expectedErr == ErrFeeTooHigh {
+					assert.ErrorIs(t, err, ErrFeeTooHigh)
+				} else {
+					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint64(txSize), plan.Size)
+			assert.Equal(t, tt.expectedFee, plan.Fee)
+
+			// The signed transaction pays the same fee
+			tx, err := SendUtxos(config)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(100000-5000)-tt.expectedFee, tx.Outputs[1].Satoshis)
+		})
+	}
+}
+
+// TestMinerPolicyFeeRateProvider tests fetching the fee rate from a policy endpoint
+// TestDynamicFeeFetchesOnce tests that a build asks the provider for the rate once
+func TestDynamicFeeFetchesOnce(t *testing.T) {
+	t.Run("split change", func(t *testing.T) {
+		provider := &stubFeeRateProvider{rate: &FeeRate{Satoshis: 1, Bytes: 10}}
+		config := feeModelTestConfig(t)
+		config.FeeModel = &CappedFee{Model: &DynamicFee{Provider: provider}, Max: 1000}
+		config.ChangeSplit = &ChangeSplit{Outputs: 5}
+
+		_, err := SendUtxos(config)
+		require.NoError(t, err)
+		assert.Equal(t, 1, provider.calls)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		provider := &stubFeeRateProvider{rate: &FeeRate{Satoshis: 1, Bytes: 10}}
+		config := feeModelTestConfig(t)
+		config.FeeModel = &DynamicFee{Provider: provider}
+		config.Payments[0].Satoshis = 100000
+
+		_, err := SendUtxos(config)
+		require.ErrorIs(t, err, ErrInsufficientFunds)
+		assert.Equal(t, 1, provider.calls)
+	})
+}
+
+func TestMinerPolicyFeeRateProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"policy":{"maxscriptsizepolicy":100000000,"miningFee":{"satoshis":1,"bytes":1000}}}`))
+	}))
+	defer server.Close()
+
+	t.Run("fetches the mining fee", func(t *testing.T) {
+		provider := &MinerPolicyFeeRateProvider{URL: server.URL, APIKey: "test-key"}
+
+		rate, err := provider.FeeRate()
+		require.NoError(t, err)
+		assert.Equal(t, &FeeRate{Satoshis: 1, Bytes: 1000}, rate)
+
+		// Used as a dynamic fee model
+		config := feeModelTestConfig(t)
+		config.FeeModel = &DynamicFee{Provider: provider}
+
+		plan, err := EstimateSendUtxos(config)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), plan.Fee)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		provider := &MinerPolicyFeeRateProvider{URL: server.URL}
+
+		_, err := provider.FeeRate()
+		assert.Error(t, err)
+	})
+}
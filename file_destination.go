@@ -0,0 +1,181 @@
+package ordinals
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+)
+
+// FileOptions configures how file content is inscribed
+type FileOptions struct {
+	// ContentType overrides the detected content type
+	ContentType string
+	// MaxSize is the largest content in bytes, DEFAULT_MAX_INSCRIPTION_SIZE if 0
+	MaxSize int64
+}
+
+// extensionContentTypes are content types that can't be reliably sniffed from the content
+var extensionContentTypes = map[string]string{
+	".svg":      "image/svg+xml",
+	".json":     "application/json",
+	".md":       "text/markdown; charset=utf-8",
+	".markdown": "text/markdown; charset=utf-8",
+	".html":     "text/html; charset=utf-8",
+	".htm":      "text/html; charset=utf-8",
+	".webp":     "image/webp",
+	".avif":     "image/avif",
+	".gltf":     "model/gltf+json",
+	".glb":      "model/gltf-binary",
+}
+
+// NewDestinationFromFile creates a Destination inscribing a File
+// The content type is taken from options, then from the File, and is sniffed from the content if both are empty.
+// It fails with ErrEmptyContent if the file is empty and ErrContentTooLarge if it is above the size limit.
+func NewDestinationFromFile(address string, file *File, options *FileOptions) (*Destination, error) {
+	if file == nil {
+		return nil, fmt.Errorf("%w: file is required", ErrEmptyContent)
+	}
+
+	if options == nil {
+		options = &FileOptions{}
+	}
+	if options.ContentType == "" {
+		options = &FileOptions{ContentType: file.ContentType, MaxSize: options.MaxSize}
+	}
+
+	return newFileDestination(address, "", file.Content, options)
+}
+
+// NewDestinationFromReader creates a Destination inscribing everything read from r
+// name is optional, its extension is used to detect types sniffing can't tell apart, e.g. ".svg" or ".md".
+// Reading stops as soon as the size limit is exceeded.
+func NewDestinationFromReader(address string, r io.Reader, name string, options *FileOptions) (*Destination, error) {
+	if options == nil {
+		options = &FileOptions{}
+	}
+
+	// Read one byte past the limit to find out if the content is too large
+	content, err := io.ReadAll(io.LimitReader(r, maxInscriptionSize(options)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	return newFileDestination(address, name, content, options)
+}
+
+// NewDestinationFromPath creates a Destination inscribing the file at path
+func NewDestinationFromPath(address, path string, options *FileOptions) (*Destination, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return NewDestinationFromReader(address, f, filepath.Base(path), options)
+}
+
+// DetectContentType returns the MIME type of content
+// The extension of name is used first for types that can't be reliably sniffed, then the
+// content is sniffed, recognising SVG, JSON, glTF and AVIF on top of net/http.
+func DetectContentType(name string, content []byte) string {
+	if contentType, ok := extensionContentTypes[strings.ToLower(filepath.Ext(name))]; ok {
+		return contentType
+	}
+
+	sniffed := http.DetectContentType(content)
+	switch {
+	case bytes.HasPrefix(content, []byte("glTF")):
+		return "model/gltf-binary"
+	case len(content) >= 12 && string(content[4:8]) == "ftyp" &&
+		(string(content[8:12]) == "avif" || string(content[8:12]) == "avis"):
+		return "image/avif"
+	case strings.HasPrefix(sniffed, "text/plain") || strings.HasPrefix(sniffed, "text/xml"):
+		trimmed := bytes.TrimSpace(content)
+		if isSvg(trimmed) {
+			return "image/svg+xml"
+		}
+		if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+			if isGltf(trimmed) {
+				return "model/gltf+json"
+			}
+			return "application/json"
+		}
+	}
+
+	return sniffed
+}
+
+// newFileDestination checks the content and creates the inscription destination
+func newFileDestination(address, name string, content []byte, options *FileOptions) (*Destination, error) {
+	if _, err := parseAddress(address); err != nil {
+		return nil, err
+	}
+
+	if len(content) == 0 {
+		return nil, ErrEmptyContent
+	}
+	if maxSize := maxInscriptionSize(options); int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("%w: content is larger than %d bytes", ErrContentTooLarge, maxSize)
+	}
+
+	contentType := options.ContentType
+	if contentType == "" {
+		contentType = DetectContentType(name, content)
+	}
+
+	return &Destination{
+		Address: address,
+		Inscription: &inscription.Inscription{
+			File: inscription.File{
+				Type:    contentType,
+				Content: content,
+			},
+		},
+	}, nil
+}
+
+// maxInscriptionSize returns the size limit of the options
+func maxInscriptionSize(options *FileOptions) int64 {
+	if options.MaxSize > 0 {
+		return options.MaxSize
+	}
+
+	return DEFAULT_MAX_INSCRIPTION_SIZE
+}
+
+// isSvg reports whether text is an SVG document, possibly after an XML declaration and comments
+func isSvg(text []byte) bool {
+	if len(text) == 0 || text[0] != '<' {
+		return false
+	}
+
+	head := text
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+
+	return bytes.Contains(bytes.ToLower(head), []byte("<svg"))
+}
+
+// isGltf reports whether a JSON document is a glTF asset
+func isGltf(text []byte) bool {
+	var doc struct {
+		Asset *struct {
+			Version string `json:"version"`
+		} `json:"asset"`
+	}
+	if err := json.Unmarshal(text, &doc); err != nil {
+		return false
+	}
+
+	return doc.Asset != nil && doc.Asset.Version != ""
+}
@@ -0,0 +1,104 @@
+package ordinals
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDetectContentType tests sniffing and extension overrides
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		expected string
+	}{
+		{"png", "", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png"},
+		{"plain text", "", "hello", "text/plain; charset=utf-8"},
+		{"svg", "", `<svg xmlns="http://www.w3.org/2000/svg"></svg>`, "image/svg+xml"},
+		{"svg with xml declaration", "", `<?xml version="1.0"?><svg></svg>`, "image/svg+xml"},
+		{"text mentioning svg", "", `use an <svg> element`, "text/plain; charset=utf-8"},
+		{"json", "", `{"name": "test"}`, "application/json"},
+		{"gltf", "", `{"asset": {"version": "2.0"}}`, "model/gltf+json"},
+		{"glb", "", "glTF\x02\x00\x00\x00", "model/gltf-binary"},
+		{"avif", "", "\x00\x00\x00\x1cftypavif\x00\x00\x00\x00", "image/avif"},
+		{"webp", "", "RIFF\x00\x00\x00\x00WEBPVP8 ", "image/webp"},
+		{"html", "", "<!DOCTYPE html><html></html>", "text/html; charset=utf-8"},
+		{"markdown by extension", "README.md", "# Title", "text/markdown; charset=utf-8"},
+		{"extension is case insensitive", "logo.SVG", "<svg></svg>", "image/svg+xml"},
+		{"unknown extension is sniffed", "notes.xyz", "hello", "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectContentType(tt.filename, []byte(tt.content)))
+		})
+	}
+}
+
+// TestNewDestination tests building inscription destinations from files, readers and paths
+func TestNewDestination(t *testing.T) {
+	address := "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"
+
+	t.Run("file", func(t *testing.T) {
+		dest, err := NewDestinationFromFile(address, &File{Content: []byte(`{"a": 1}`)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, address, dest.Address)
+		assert.Equal(t, "application/json", dest.Inscription.File.Type)
+		assert.Equal(t, []byte(`{"a": 1}`), dest.Inscription.File.Content)
+
+		// The File content type is used over sniffing, options over both
+		dest, err = NewDestinationFromFile(address, &File{Content: []byte("hello"), ContentType: "text/x-custom"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "text/x-custom", dest.Inscription.File.Type)
+
+		dest, err = NewDestinationFromFile(address, &File{Content: []byte("hello"), ContentType: "text/x-custom"}, &FileOptions{ContentType: "text/x-override"})
+		require.NoError(t, err)
+		assert.Equal(t, "text/x-override", dest.Inscription.File.Type)
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		dest, err := NewDestinationFromReader(address, strings.NewReader("# Title"), "notes.md", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "text/markdown; charset=utf-8", dest.Inscription.File.Type)
+	})
+
+	t.Run("path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "logo.svg")
+		require.NoError(t, os.WriteFile(path, []byte("<svg></svg>"), 0o600))
+
+		dest, err := NewDestinationFromPath(address, path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", dest.Inscription.File.Type)
+
+		_, err = NewDestinationFromPath(address, filepath.Join(t.TempDir(), "missing.png"), nil)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		_, err := NewDestinationFromFile(address, &File{}, nil)
+		require.ErrorIs(t, err, ErrEmptyContent)
+
+		_, err = NewDestinationFromReader(address, strings.NewReader(""), "", nil)
+		require.ErrorIs(t, err, ErrEmptyContent)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		_, err := NewDestinationFromReader(address, strings.NewReader("12345"), "", &FileOptions{MaxSize: 4})
+		require.ErrorIs(t, err, ErrContentTooLarge)
+
+		dest, err := NewDestinationFromReader(address, strings.NewReader("1234"), "", &FileOptions{MaxSize: 4})
+		require.NoError(t, err)
+		assert.Len(t, dest.Inscription.File.Content, 4)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := NewDestinationFromFile("invalid", &File{Content: []byte("hello")}, nil)
+		require.ErrorIs(t, err, ErrInvalidAddress)
+	})
+}
@@ -0,0 +1,149 @@
+package ordinals
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileStore is a goroutine-safe KVStore and KVBatcher persisted to a JSON file
+// Every Set, Delete or Apply rewrites the file through a temporary file, so a crash never leaves it half written.
+// Apply saves many writes with one rewrite.
+type FileStore struct {
+	mu   sync.RWMutex
+	path string
+	data map[string][]byte
+}
+
+// NewFileStore opens the store at path, creating it on the first write if it doesn't exist
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, data: make(map[string][]byte)}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+
+	if err = json.Unmarshal(content, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to decode store: %w", err)
+	}
+
+	return s, nil
+}
+
+// Get returns the value of a key, or nil if the key doesn't exist
+func (s *FileStore) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.data[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+// Set stores the value of a key and saves the file
+func (s *FileStore) Set(key string, value []byte) error {
+	return s.Apply([]KVWrite{{Key: key, Value: append([]byte{}, value...)}})
+}
+
+// Delete removes a key and saves the file
+func (s *FileStore) Delete(key string) error {
+	return s.Apply([]KVWrite{{Key: key}})
+}
+
+// Apply makes the writes in order and saves the file once
+func (s *FileStore) Apply(writes []KVWrite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Remember the previous values to keep memory and file in sync if the file can't be written
+	previous := make(map[string][]byte)
+	changed := false
+	for _, write := range writes {
+		value, existed := s.data[write.Key]
+		if _, ok := previous[write.Key]; !ok {
+			previous[write.Key] = value
+		}
+
+		if write.Value == nil {
+			delete(s.data, write.Key)
+			changed = changed || existed
+		} else {
+			s.data[write.Key] = append([]byte(nil), write.Value...)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := s.save(); err != nil {
+		for key, value := range previous {
+			if value == nil {
+				delete(s.data, key)
+			} else {
+				s.data[key] = value
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Keys returns the keys starting with prefix in sorted order
+func (s *FileStore) Keys(prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// save writes the data to a temporary file and renames it over the store
+func (s *FileStore) save() error {
+	content, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary store file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save store: %w", err)
+	}
+
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/bsv-blockchain/go-sdk/transaction"
 )
@@ -16,6 +18,20 @@ const (
 	OneSatApiBase = "https://ordinals.gorillapool.io/api/v1"
 )
 
+// Client calls the 1Sat API
+// The package level Fetch functions and OneSatBroadcaster use a Client with the defaults.
+type Client struct {
+	// BaseURL is the API base URL, defaults to OneSatApiBase
+	BaseURL string
+	// HTTPClient is the HTTP client to use, defaults to http.DefaultClient
+	HTTPClient *http.Client
+	// Logger receives debug logs of every HTTP call, nothing is logged if nil
+	Logger *slog.Logger
+}
+
+// defaultClient is used by the package level functions
+var defaultClient = &Client{}
+
 // UTXOResponse represents a UTXO response from the 1Sat API
 type UTXOResponse struct {
 	Txid   string `json:"txid"`
@@ -26,26 +42,36 @@ type UTXOResponse struct {
 }
 
 // FetchPayUtxos fetches UTXOs for payment from the 1Sat API
+// UTXOs that may hold an ordinal are excluded, see FetchPayUtxosWithScreen.
 func FetchPayUtxos(address string) ([]*Utxo, error) {
-	url := fmt.Sprintf("%s/address/%s/utxo", OneSatApiBase, address)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch pay UTXOs: %w", err)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Error closing response body: %v\n", err)
-		}
-	}()
+	return defaultClient.FetchPayUtxos(address)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// FetchPayUtxosWithScreen fetches UTXOs for payment from the 1Sat API and screens them
+// It returns the UTXOs safe to spend and the ones excluded by the screen.
+func FetchPayUtxosWithScreen(address string, screen PaymentScreen) ([]*Utxo, []*ExcludedUtxo, error) {
+	return defaultClient.FetchPayUtxosWithScreen(address, screen)
+}
+
+// FetchPayUtxos fetches UTXOs for payment from the 1Sat API
+// UTXOs that may hold an ordinal are excluded, see FetchPayUtxosWithScreen.
+func (c *Client) FetchPayUtxos(address string) ([]*Utxo, error) {
+	utxos, _, err := c.FetchPayUtxosWithScreen(address, PaymentScreen{})
+	return utxos, err
+}
+
+// FetchPayUtxosWithScreen fetches UTXOs for payment from the 1Sat API and screens them
+// It returns the UTXOs safe to spend and the ones excluded by the screen.
+func (c *Client) FetchPayUtxosWithScreen(address string, screen PaymentScreen) ([]*Utxo, []*ExcludedUtxo, error) {
+	url := fmt.Sprintf("%s/address/%s/utxo", c.baseURL(), address)
+	body, _, err := c.do(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch pay UTXOs: %w", err)
 	}
 
 	var utxoResp []UTXOResponse
 	if err := json.Unmarshal(body, &utxoResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	utxos := make([]*Utxo, 0, len(utxoResp))
@@ -58,7 +84,13 @@ func FetchPayUtxos(address string) ([]*Utxo, error) {
 		})
 	}
 
-	return utxos, nil
+	// Leave out UTXOs that may hold an ordinal
+	utxos, excluded := ScreenPaymentUtxos(utxos, screen)
+	for _, e := range excluded {
+		c.log().Debug("excluded payment utxo", "outpoint", fmt.Sprintf("%s_%d", e.Utxo.TxID, e.Utxo.Vout), "reason", e.Reason)
+	}
+
+	return utxos, excluded, nil
 }
 
 // NftUtxoResponse represents an NFT UTXO response from the 1Sat API
@@ -70,25 +102,20 @@ type NftUtxoResponse struct {
 
 // FetchNftUtxos fetches NFT UTXOs from the 1Sat API
 func FetchNftUtxos(address string, collectionID string) ([]*NftUtxo, error) {
-	url := fmt.Sprintf("%s/address/%s/ordinals", OneSatApiBase, address)
+	return defaultClient.FetchNftUtxos(address, collectionID)
+}
+
+// FetchNftUtxos fetches NFT UTXOs from the 1Sat API
+func (c *Client) FetchNftUtxos(address string, collectionID string) ([]*NftUtxo, error) {
+	url := fmt.Sprintf("%s/address/%s/ordinals", c.baseURL(), address)
 	if collectionID != "" {
 		url += "?collection=" + collectionID
 	}
 
-	resp, err := http.Get(url)
+	body, _, err := c.do(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch NFT UTXOs: %w", err)
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Error closing response body: %v\n", err)
-		}
-	}()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
 
 	var utxoResp []NftUtxoResponse
 	if err := json.Unmarshal(body, &utxoResp); err != nil {
@@ -123,25 +150,20 @@ type TokenUtxoResponse struct {
 
 // FetchTokenUtxos fetches token UTXOs from the 1Sat API
 func FetchTokenUtxos(protocol TokenType, tokenID string, address string) ([]*TokenUtxo, error) {
-	url := fmt.Sprintf("%s/address/%s/tokens?protocol=%s", OneSatApiBase, address, protocol)
+	return defaultClient.FetchTokenUtxos(protocol, tokenID, address)
+}
+
+// FetchTokenUtxos fetches token UTXOs from the 1Sat API
+func (c *Client) FetchTokenUtxos(protocol TokenType, tokenID string, address string) ([]*TokenUtxo, error) {
+	url := fmt.Sprintf("%s/address/%s/tokens?protocol=%s", c.baseURL(), address, protocol)
 	if tokenID != "" {
 		url += "&id=" + tokenID
 	}
 
-	resp, err := http.Get(url)
+	body, _, err := c.do(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch token UTXOs: %w", err)
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Error closing response body: %v\n", err)
-		}
-	}()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
 
 	var utxoResp []TokenUtxoResponse
 	if err := json.Unmarshal(body, &utxoResp); err != nil {
@@ -174,8 +196,13 @@ func FetchTokenUtxos(protocol TokenType, tokenID string, address string) ([]*Tok
 
 // OneSatBroadcaster returns a function for broadcasting transactions using the 1Sat API
 func OneSatBroadcaster() BroadcastFunc {
+	return defaultClient.Broadcaster()
+}
+
+// Broadcaster returns a function for broadcasting transactions using the 1Sat API
+func (c *Client) Broadcaster() BroadcastFunc {
 	return func(tx *transaction.Transaction) (*BroadcastResult, error) {
-		url := fmt.Sprintf("%s/tx", OneSatApiBase)
+		url := fmt.Sprintf("%s/tx", c.baseURL())
 
 		// Get the transaction hex
 		txHex := tx.String()
@@ -188,24 +215,13 @@ func OneSatBroadcaster() BroadcastFunc {
 		}
 
 		// Make the request
-		resp, err := http.Post(url, "application/json", bytes.NewBuffer(reqJSON))
+		body, status, err := c.do(http.MethodPost, url, reqJSON)
 		if err != nil {
 			return nil, fmt.Errorf("failed to broadcast transaction: %w", err)
 		}
-		defer func() {
-			if err := resp.Body.Close(); err != nil {
-				fmt.Printf("Error closing response body: %v\n", err)
-			}
-		}()
-
-		// Parse the response
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
-		}
 
 		// Check for success
-		if resp.StatusCode != http.StatusOK {
+		if status != http.StatusOK {
 			return &BroadcastResult{
 				Status:  "error",
 				Message: string(body),
@@ -219,3 +235,58 @@ func OneSatBroadcaster() BroadcastFunc {
 		}, nil
 	}
 }
+
+// do sends a request with an optional JSON body and returns the response body and status code
+func (c *Client) do(method, url string, reqBody []byte) ([]byte, int, error) {
+	var reader io.Reader
+	if reqBody != nil {
+		reader = bytes.NewReader(reqBody)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		c.log().Debug("http request failed", "method", method, "url", url, "duration", time.Since(start), "error", err)
+		return nil, 0, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.log().Warn("failed to close response body", "url", url, "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	c.log().Debug("http request", "method", method, "url", url, "status", resp.StatusCode, "bytes", len(body), "duration", time.Since(start))
+	return body, resp.StatusCode, nil
+}
+
+// baseURL returns the API base URL
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return OneSatApiBase
+	}
+
+	return c.BaseURL
+}
+
+// log returns the logger of the client
+func (c *Client) log() *slog.Logger {
+	return loggerOrDiscard(c.Logger)
+}
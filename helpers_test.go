@@ -1,11 +1,15 @@
 package ordinals
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock RoundTripper that redirects requests to our test server
@@ -197,3 +201,30 @@ func TestHelpers(t *testing.T) {
 	err = fetchUtxo(utxo)
 	assert.Error(t, err)
 }
+
+// TestClientLogger tests that HTTP calls are logged to the client logger
+func TestClientLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/address/test_address/utxo", r.URL.Path)
+		_, err := w.Write([]byte(`[]`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := &Client{
+		BaseURL: server.URL,
+		Logger:  slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	utxos, err := client.FetchPayUtxos("test_address")
+	require.NoError(t, err)
+	assert.Empty(t, utxos)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, "http request", record["msg"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, server.URL+"/address/test_address/utxo", record["url"])
+	assert.Equal(t, float64(http.StatusOK), record["status"])
+}
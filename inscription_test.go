@@ -1,13 +1,19 @@
 package ordinals
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
 	"github.com/bitcoin-sv/go-templates/template/inscription"
 	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // fetchUtxo is a mock function for fetching UTXO data in tests
@@ -594,8 +600,13 @@ func TestDeployBsv21Token(t *testing.T) {
 		assert.Error(t, err, "Expected error due to insufficient funds")
 		assert.Nil(t, tx, "Expected tx to be nil")
 		if err != nil {
-			// The actual error message is "satoshis inputted to the tx are less than the outputted satoshis"
-			assert.Contains(t, err.Error(), "less than", "Error should indicate insufficient funds")
+			assert.ErrorIs(t, err, ErrInsufficientFunds, "Error should indicate insufficient funds")
+
+			var fundsErr *InsufficientFundsError
+			if assert.ErrorAs(t, err, &fundsErr) {
+				assert.Equal(t, insufficientUtxo.Satoshis, fundsErr.Available)
+				assert.Greater(t, fundsErr.Needed, fundsErr.Available)
+			}
 		}
 	})
 
@@ -690,6 +701,228 @@ func TestTransferOrdTokens(t *testing.T) {
 	assert.Nil(t, tx)
 }
 
+// TestTransferOrdTokensNeededMode tests that only the selected token inputs are spent
+func TestTransferOrdTokensNeededMode(t *testing.T) {
+	// Create private keys
+	paymentPk, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	ordPk, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentUtxo := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}
+
+	newTokenUtxo := func(vout uint32, amount uint64) *TokenUtxo {
+		return &TokenUtxo{
+			Utxo: Utxo{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+				Vout:         vout,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     1,
+			},
+			TokenID:  tokenID,
+			Protocol: TokenTypeBSV21,
+			Amount:   amount,
+		}
+	}
+
+	tokenUtxos := []*TokenUtxo{
+		newTokenUtxo(0, 600),
+		newTokenUtxo(1, 500),
+		newTokenUtxo(2, 200),
+	}
+
+	baseCfg := &TransferBsv21TokenConfig{
+		Protocol:    TokenTypeBSV21,
+		TokenID:     tokenID,
+		Utxos:       []*Utxo{paymentUtxo},
+		InputTokens: tokenUtxos,
+		Distributions: []*TokenDistribution{
+			{
+				Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+				Tokens:  500,
+			},
+		},
+		PaymentPk:      paymentPk,
+		OrdPk:          ordPk,
+		ChangeAddress:  "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		TokenInputMode: TokenInputModeNeeded,
+	}
+
+	t.Run("retain order", func(t *testing.T) {
+		cfg := *baseCfg
+
+		result, err := TransferOrdTokensWithResult(&cfg)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, result) {
+			return
+		}
+
+		assert.Equal(t, tokenUtxos[:1], result.SpentTokens)
+		assert.Equal(t, tokenUtxos[1:], result.UnspentTokens)
+
+		// 1 token input + 1 payment input; distribution, token change and payment change
+		assert.Equal(t, 2, len(result.Tx.Inputs))
+		assert.Equal(t, 3, len(result.Tx.Outputs))
+	})
+
+	t.Run("smallest first", func(t *testing.T) {
+		cfg := *baseCfg
+		cfg.SelectionOptions = &TokenSelectionOptions{
+			InputStrategy: TokenSelectionStrategySmallestFirst,
+		}
+
+		result, err := TransferOrdTokensWithResult(&cfg)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, result) {
+			return
+		}
+
+		// 200 + 500 covers the transfer, 600 stays untouched
+		assert.Equal(t, []*TokenUtxo{tokenUtxos[2], tokenUtxos[1]}, result.SpentTokens)
+		assert.Equal(t, []*TokenUtxo{tokenUtxos[0]}, result.UnspentTokens)
+	})
+
+	t.Run("all mode spends every input", func(t *testing.T) {
+		cfg := *baseCfg
+		cfg.TokenInputMode = TokenInputModeAll
+
+		result, err := TransferOrdTokensWithResult(&cfg)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, result) {
+			return
+		}
+
+		assert.Equal(t, tokenUtxos, result.SpentTokens)
+		assert.Empty(t, result.UnspentTokens)
+		assert.Equal(t, 4, len(result.Tx.Inputs))
+	})
+}
+
+// TestTransferOrdTokensTokenChangeAddress tests routing token change without an OrdPk
+func TestTransferOrdTokensTokenChangeAddress(t *testing.T) {
+	// Create payment private key, the token inputs are signed externally
+	paymentPk, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+	tokenChangeAddress := "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"
+
+	// Token change outputs end with the P2PKH script of the token change address
+	addr, err := script.NewAddressFromString(tokenChangeAddress)
+	assert.NoError(t, err)
+	tokenChangeScript, err := p2pkh.Lock(addr)
+	assert.NoError(t, err)
+
+	newConfig := func() *TransferBsv21TokenConfig {
+		return &TransferBsv21TokenConfig{
+			Protocol: TokenTypeBSV21,
+			TokenID:  tokenID,
+			Utxos: []*Utxo{{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+				Vout:         0,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     100000,
+			}},
+			InputTokens: []*TokenUtxo{{
+				Utxo: Utxo{
+					TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+					Vout:         0,
+					ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+					Satoshis:     1,
+				},
+				TokenID:  tokenID,
+				Protocol: TokenTypeBSV21,
+				Amount:   1000,
+			}},
+			Distributions: []*TokenDistribution{
+				{
+					Address: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+					Tokens:  400,
+				},
+			},
+			PaymentPk:          paymentPk,
+			ChangeAddress:      "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+			TokenChangeAddress: tokenChangeAddress,
+		}
+	}
+
+	t.Run("token change goes to token change address", func(t *testing.T) {
+		tx, err := TransferOrdTokens(newConfig())
+		assert.NoError(t, err)
+		if !assert.NotNil(t, tx) {
+			return
+		}
+
+		// Distribution, token change and payment change
+		assert.Equal(t, 3, len(tx.Outputs))
+
+		assert.True(t, bytes.HasSuffix(*tx.Outputs[1].LockingScript, *tokenChangeScript))
+
+		// The token input is left for an external signer, the payment input is signed
+		assert.Nil(t, tx.Inputs[0].UnlockingScript)
+		assert.NotNil(t, tx.Inputs[1].UnlockingScript)
+	})
+
+	t.Run("split change goes to token change address", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.SplitConfig = &TokenSplitConfig{Outputs: 2}
+
+		tx, err := TransferOrdTokens(cfg)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, tx) {
+			return
+		}
+
+		for _, output := range tx.Outputs[1:3] {
+			assert.True(t, bytes.HasSuffix(*output.LockingScript, *tokenChangeScript))
+		}
+	})
+
+	t.Run("no token change address or ordPk", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.TokenChangeAddress = ""
+
+		_, err := TransferOrdTokens(cfg)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "tokenChangeAddress or ordPk required")
+	})
+
+	t.Run("burn drops the token change", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.TokenChangeAddress = ""
+		cfg.Burn = true
+
+		tx, err := TransferOrdTokens(cfg)
+		require.NoError(t, err)
+
+		// Distribution and payment change, no token change or burn output
+		require.Len(t, tx.Outputs, 2)
+		assert.Nil(t, decodeBsv21(tx.Outputs[1].LockingScript))
+	})
+
+	t.Run("burn remaining records a burn", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.BurnRemaining = true
+
+		tx, err := TransferOrdTokens(cfg)
+		require.NoError(t, err)
+
+		require.Len(t, tx.Outputs, 3)
+		burn := decodeBsv21(tx.Outputs[1].LockingScript)
+		require.NotNil(t, burn)
+		assert.Equal(t, string(bsv21.OpBurn), burn.Op)
+		assert.Equal(t, uint64(600), burn.Amt)
+	})
+}
+
 func TestCreateOrdListings(t *testing.T) {
 	// Create private keys
 	paymentPk, err := ec.NewPrivateKey()
@@ -751,6 +984,50 @@ func TestCreateOrdListings(t *testing.T) {
 	assert.Equal(t, uint64(1), tx.Outputs[0].Satoshis, "Ordlock output should be 1 satoshi")
 }
 
+// listOrdinal lists a 1 sat ordinal for price satoshis and returns the listing UTXO
+// sellerAddress can cancel the listing and payAddress receives the price.
+func listOrdinal(t *testing.T, sellerAddress, payAddress string, price uint64) *NftUtxo {
+	t.Helper()
+
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	tx, err := CreateOrdListings(&CreateOrdListingsConfig{
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     100000,
+		}},
+		Listings: []*struct {
+			PayAddress  string
+			Price       uint64
+			ListingUtxo *NftUtxo
+			OrdAddress  string
+		}{{
+			PayAddress: payAddress,
+			Price:      price,
+			ListingUtxo: &NftUtxo{Utxo: Utxo{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+				Vout:         1,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     1,
+			}},
+			OrdAddress: sellerAddress,
+		}},
+		PaymentPk: paymentPk,
+		OrdPk:     paymentPk,
+	})
+	require.NoError(t, err)
+
+	return &NftUtxo{Utxo: Utxo{
+		TxID:         tx.TxID().String(),
+		Vout:         0,
+		ScriptPubKey: tx.Outputs[0].LockingScript.String(),
+		Satoshis:     1,
+	}}
+}
+
 func TestPurchaseOrdListing(t *testing.T) {
 	// Create a private key for payment
 	paymentPk, err := ec.NewPrivateKey()
@@ -764,16 +1041,7 @@ func TestPurchaseOrdListing(t *testing.T) {
 		Satoshis:     100000,
 	}
 
-	listingUtxo := &NftUtxo{
-		Utxo: Utxo{
-			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
-			Vout:         1,
-			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
-			Satoshis:     1,
-		},
-		ContentType:  "text/plain",
-		CollectionID: "collection123",
-	}
+	listingUtxo := listOrdinal(t, "1BitcoinEaterAddressDontSendf59kuE", "1BitcoinEaterAddressDontSendf59kuE", 50000)
 
 	// Create a test configuration
 	config := &PurchaseOrdListingConfig{
@@ -786,15 +1054,42 @@ func TestPurchaseOrdListing(t *testing.T) {
 
 	// Create the transaction
 	tx, err := PurchaseOrdListing(config)
-
-	// We expect the transaction to be created successfully
-	assert.NoError(t, err)
-	assert.NotNil(t, tx)
+	require.NoError(t, err)
 
 	// Verify the transaction structure
-	assert.Equal(t, 2, len(tx.Inputs), "Should have 2 inputs: payment and listing")
-	assert.Equal(t, 2, len(tx.Outputs), "Should have 2 outputs: ordinal and change")
+	assert.Equal(t, 2, len(tx.Inputs), "Should have 2 inputs: listing and payment")
+	assert.Equal(t, 3, len(tx.Outputs), "Should have 3 outputs: ordinal, payout and change")
 	assert.Equal(t, uint64(1), tx.Outputs[0].Satoshis, "Ordinal output should be 1 satoshi")
+	assert.Equal(t, uint64(50000), tx.Outputs[1].Satoshis, "Payout should be the listing price")
+
+	// The listing script accepts the purchase
+	verifyInput(t, tx, 0)
+
+	t.Run("payout moved", func(t *testing.T) {
+		// The listing requires its payout in output 1
+		tx.Outputs[1], tx.Outputs[2] = tx.Outputs[2], tx.Outputs[1]
+		tx.Inputs[0].UnlockingScript, err = (&ordLockPurchase{}).Sign(tx, 0)
+		require.NoError(t, err)
+
+		err = interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("not a listing", func(t *testing.T) {
+		config.ListingUtxo = &NftUtxo{Utxo: Utxo{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         1,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     1,
+		}}
+
+		_, err := PurchaseOrdListing(config)
+		assert.ErrorContains(t, err, "not an Ordinal Lock")
+	})
 }
 
 func TestCancelOrdListings(t *testing.T) {
@@ -813,16 +1108,10 @@ func TestCancelOrdListings(t *testing.T) {
 		Satoshis:     100000,
 	}
 
-	listingUtxo := &NftUtxo{
-		Utxo: Utxo{
-			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
-			Vout:         1,
-			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
-			Satoshis:     1,
-		},
-		ContentType:  "text/plain",
-		CollectionID: "collection123",
-	}
+	// The listing can be cancelled by OrdPk
+	sellerAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+	require.NoError(t, err)
+	listingUtxo := listOrdinal(t, sellerAddr.AddressString, "1BitcoinEaterAddressDontSendf59kuE", 50000)
 
 	// Create a test configuration
 	config := &CancelOrdListingsConfig{
@@ -841,9 +1130,12 @@ func TestCancelOrdListings(t *testing.T) {
 	assert.NotNil(t, tx)
 
 	// Verify the transaction structure
-	assert.Equal(t, 2, len(tx.Inputs), "Should have 2 inputs: payment and listing")
+	assert.Equal(t, 2, len(tx.Inputs), "Should have 2 inputs: listing and payment")
 	assert.Equal(t, 2, len(tx.Outputs), "Should have 2 outputs: ordinal and change")
 	assert.Equal(t, uint64(1), tx.Outputs[0].Satoshis, "Ordinal output should be 1 satoshi")
+
+	// The listing script accepts the seller's signature
+	verifyInput(t, tx, 0)
 }
 
 func TestTokenSplitConfig(t *testing.T) {
@@ -1244,6 +1536,132 @@ func TestBurnOrdinals(t *testing.T) {
 	})
 }
 
+// TestBurnOrdTokens tests burning tokens with an explicit burn operation
+func TestBurnOrdTokens(t *testing.T) {
+	// Create private keys for payment and tokens
+	paymentPk, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	// Prepare test payment UTXO
+	paymentUtxo := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}
+
+	// Prepare test token UTXO
+	tokenUtxo := &TokenUtxo{
+		Utxo: Utxo{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     1,
+		},
+		TokenID:  tokenID,
+		Protocol: TokenTypeBSV21,
+		Amount:   1000,
+	}
+
+	// decodeTokenOp returns the token JSON inscribed in an output
+	decodeTokenOp := func(t *testing.T, s *script.Script) map[string]interface{} {
+		insc := inscription.Decode(s)
+		if !assert.NotNil(t, insc) {
+			return nil
+		}
+		assert.Equal(t, "application/bsv-20", insc.File.Type)
+		data := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(insc.File.Content, &data))
+		return data
+	}
+
+	// Test Case 1: Burn part of the tokens with a reason
+	t.Run("Burn partial amount with metadata", func(t *testing.T) {
+		config := &BurnOrdTokensConfig{
+			Protocol:      TokenTypeBSV21,
+			TokenID:       tokenID,
+			PaymentUtxos:  []*Utxo{paymentUtxo},
+			PaymentPk:     paymentPk,
+			InputTokens:   []*TokenUtxo{tokenUtxo},
+			OrdPk:         ordPk,
+			Amount:        300,
+			ChangeAddress: "1BitcoinEaterAddressDontSendf59kuE",
+			Metadata: map[string][]byte{
+				"app":    []byte("testapp"),
+				"type":   []byte("burn"),
+				"reason": []byte("supply reduction"),
+			},
+		}
+
+		tx, err := BurnOrdTokens(config)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, tx) {
+			return
+		}
+
+		// Outputs: burn, OP_RETURN, token change, payment change
+		assert.Equal(t, 4, len(tx.Outputs))
+
+		burn := decodeTokenOp(t, tx.Outputs[0].LockingScript)
+		assert.Equal(t, "burn", burn["op"])
+		assert.Equal(t, tokenID, burn["id"])
+		assert.Equal(t, float64(300), burn["amt"])
+
+		assert.Equal(t, uint64(0), tx.Outputs[1].Satoshis, "OP_RETURN output should be 0 satoshis")
+		assert.True(t, tx.Outputs[1].LockingScript.IsData())
+
+		change := decodeTokenOp(t, tx.Outputs[2].LockingScript)
+		assert.Equal(t, "transfer", change["op"])
+		assert.Equal(t, float64(700), change["amt"])
+	})
+
+	// Test Case 2: Burn every input token
+	t.Run("Burn all tokens", func(t *testing.T) {
+		config := &BurnOrdTokensConfig{
+			Protocol:      TokenTypeBSV21,
+			TokenID:       tokenID,
+			PaymentUtxos:  []*Utxo{paymentUtxo},
+			PaymentPk:     paymentPk,
+			InputTokens:   []*TokenUtxo{tokenUtxo},
+			OrdPk:         ordPk,
+			ChangeAddress: "1BitcoinEaterAddressDontSendf59kuE",
+		}
+
+		tx, err := BurnOrdTokens(config)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, tx) {
+			return
+		}
+
+		// Outputs: burn and payment change
+		assert.Equal(t, 2, len(tx.Outputs))
+		burn := decodeTokenOp(t, tx.Outputs[0].LockingScript)
+		assert.Equal(t, float64(1000), burn["amt"])
+	})
+
+	// Test Case 3: Burning more than the inputs hold fails
+	t.Run("Burn more than available", func(t *testing.T) {
+		config := &BurnOrdTokensConfig{
+			Protocol:      TokenTypeBSV21,
+			TokenID:       tokenID,
+			PaymentUtxos:  []*Utxo{paymentUtxo},
+			PaymentPk:     paymentPk,
+			InputTokens:   []*TokenUtxo{tokenUtxo},
+			OrdPk:         ordPk,
+			Amount:        2000,
+			ChangeAddress: "1BitcoinEaterAddressDontSendf59kuE",
+		}
+
+		tx, err := BurnOrdTokens(config)
+		assert.Error(t, err)
+		assert.Nil(t, tx)
+	})
+}
+
 // TestTokenDistributionOmitMetadata tests token transfer with OmitMetadata in distributions
 func TestTokenDistributionOmitMetadata(t *testing.T) {
 	// Create private keys
@@ -0,0 +1,199 @@
+package ordinals
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// KVStore is a key-value store used to persist local state
+// Implement it on top of your own database, MemoryStore keeps everything in memory.
+type KVStore interface {
+	// Get returns the value of a key, or nil if the key doesn't exist
+	Get(key string) ([]byte, error)
+	// Set stores the value of a key
+	Set(key string, value []byte) error
+	// Delete removes a key, deleting a key that doesn't exist is not an error
+	Delete(key string) error
+	// Keys returns the keys starting with prefix in sorted order
+	Keys(prefix string) ([]string, error)
+}
+
+// KVBatcher is an optional interface of a KVStore that makes several writes as one change
+// When the store implements it, the writes of a change are made with a single Apply,
+// otherwise they are made one at a time with Set and Delete.
+type KVBatcher interface {
+	// Apply makes the writes in order as one change, either all of them are stored or none
+	Apply(writes []KVWrite) error
+}
+
+// KVWrite is one change made by KVBatcher.Apply
+type KVWrite struct {
+	Key string
+	// Value is the new value of Key, nil deletes the key
+	Value []byte
+}
+
+// MemoryStore is a goroutine-safe KVStore and KVBatcher kept in memory
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+// Get returns the value of a key, or nil if the key doesn't exist
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.data[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+// Set stores the value of a key
+func (s *MemoryStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes a key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, key)
+	return nil
+}
+
+// Apply makes the writes in order as one change
+func (s *MemoryStore) Apply(writes []KVWrite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, write := range writes {
+		if write.Value == nil {
+			delete(s.data, write.Key)
+		} else {
+			s.data[write.Key] = append([]byte(nil), write.Value...)
+		}
+	}
+
+	return nil
+}
+
+// Keys returns the keys starting with prefix in sorted order
+func (s *MemoryStore) Keys(prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// kvBatch stages writes to a KVStore and makes them on Commit, with a single Apply if the store is a KVBatcher
+// Reads see the staged writes, so a batch can replace the store while a change is built.
+type kvBatch struct {
+	store  KVStore
+	staged map[string][]byte
+	writes []KVWrite
+}
+
+// newKVBatch creates an empty batch on store
+func newKVBatch(store KVStore) *kvBatch {
+	return &kvBatch{store: store, staged: make(map[string][]byte)}
+}
+
+// Get returns the staged value of a key, or the stored one if it isn't staged
+func (b *kvBatch) Get(key string) ([]byte, error) {
+	if value, ok := b.staged[key]; ok {
+		return append([]byte(nil), value...), nil
+	}
+
+	return b.store.Get(key)
+}
+
+// Set stages the value of a key
+func (b *kvBatch) Set(key string, value []byte) error {
+	return b.Apply([]KVWrite{{Key: key, Value: append([]byte{}, value...)}})
+}
+
+// Delete stages the removal of a key
+func (b *kvBatch) Delete(key string) error {
+	return b.Apply([]KVWrite{{Key: key}})
+}
+
+// Keys returns the stored keys starting with prefix with the staged writes applied, in sorted order
+func (b *kvBatch) Keys(prefix string) ([]string, error) {
+	stored, err := b.store.Keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, key := range stored {
+		if _, ok := b.staged[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	for key, value := range b.staged {
+		if value != nil && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Apply stages the writes
+func (b *kvBatch) Apply(writes []KVWrite) error {
+	for _, write := range writes {
+		b.staged[write.Key] = write.Value
+	}
+	b.writes = append(b.writes, writes...)
+
+	return nil
+}
+
+// Commit makes the staged writes to the store
+func (b *kvBatch) Commit() error {
+	if len(b.writes) == 0 {
+		return nil
+	}
+
+	if batcher, ok := b.store.(KVBatcher); ok {
+		return batcher.Apply(b.writes)
+	}
+
+	for _, write := range b.writes {
+		var err error
+		if write.Value == nil {
+			err = b.store.Delete(write.Key)
+		} else {
+			err = b.store.Set(write.Key, write.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
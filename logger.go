@@ -0,0 +1,16 @@
+package ordinals
+
+import "log/slog"
+
+// discardLogger drops every record, it is used when no logger is configured
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// loggerOrDiscard returns logger, or a logger dropping every record if it is nil
+// The library never writes to stdout, logs only go to a logger the caller injects.
+func loggerOrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return discardLogger
+	}
+
+	return logger
+}
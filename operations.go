@@ -0,0 +1,404 @@
+package ordinals
+
+import (
+	"errors"
+	"fmt"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// Operation is one step of a transaction built by BuildOperations
+// It is implemented by InscribeOperation, SendOrdinalOperation, TransferTokensOperation,
+// ListOperation, PurchaseOperation, BurnOperation and PayOperation.
+type Operation interface {
+	operation()
+}
+
+// InscribeOperation inscribes a new ordinal
+type InscribeOperation struct {
+	// Destination is the address and inscription of the new ordinal
+	Destination *Destination
+}
+
+// SendOrdinalOperation sends an ordinal owned by OrdPk
+type SendOrdinalOperation struct {
+	// Ordinal is the 1 sat ordinal to send
+	Ordinal *NftUtxo
+	// Destination is where the ordinal is sent, optionally with a new inscription
+	Destination *Destination
+}
+
+// TransferTokensOperation transfers BSV21 tokens owned by OrdPk
+type TransferTokensOperation struct {
+	// TokenID is the ID of the token
+	TokenID string
+	// InputTokens are the token UTXOs to spend
+	InputTokens []*TokenUtxo
+	// Distributions are the token outputs to create
+	Distributions []*TokenDistribution
+	// BurnRemaining burns any remaining tokens with an explicit burn operation instead of returning them as change
+	BurnRemaining bool
+	// TokenChangeAddress is the address to send token change to, defaults to the OrdPk address
+	TokenChangeAddress string
+}
+
+// ListOperation lists an ordinal owned by OrdPk for sale with an Ordinal Lock
+type ListOperation struct {
+	// Ordinal is the 1 sat ordinal to list
+	Ordinal *NftUtxo
+	// SellerAddress can cancel the listing
+	SellerAddress string
+	// PayAddress receives Price satoshis when the listing is purchased
+	PayAddress string
+	// Price is the price in satoshis
+	Price uint64
+}
+
+// PurchaseOperation purchases an Ordinal Lock listing with the payment UTXOs
+// The price and payout are read from the listing script. Only one listing can be purchased
+// per transaction and not together with ordinals being sent or listed.
+type PurchaseOperation struct {
+	// Listing is the listed ordinal
+	Listing *NftUtxo
+	// OrdAddress receives the purchased ordinal
+	OrdAddress string
+}
+
+// BurnOperation burns ordinals owned by OrdPk by paying them to the miner as fee
+type BurnOperation struct {
+	// Ordinals are the 1 sat ordinals to burn
+	Ordinals []*NftUtxo
+	// Metadata is optional MAP protocol metadata to include in an OP_RETURN output
+	Metadata map[string][]byte
+}
+
+// PayOperation pays satoshis to an address
+type PayOperation struct {
+	// Address is the address to pay
+	Address string
+	// Satoshis is the amount to pay
+	Satoshis uint64
+}
+
+func (*InscribeOperation) operation()       {}
+func (*SendOrdinalOperation) operation()    {}
+func (*TransferTokensOperation) operation() {}
+func (*ListOperation) operation()           {}
+func (*PurchaseOperation) operation()       {}
+func (*BurnOperation) operation()           {}
+func (*PayOperation) operation()            {}
+
+// OperationsConfig represents configuration for building several operations in one transaction
+type OperationsConfig struct {
+	// Utxos are the UTXOs to use for payment
+	Utxos []*Utxo
+	// PaymentPk is the private key for the payment UTXOs
+	PaymentPk *ec.PrivateKey
+	// OrdPk is the private key for the ordinals and tokens being spent
+	OrdPk *ec.PrivateKey
+	// Operations are the operations to combine
+	Operations []Operation
+	// ChangeAddress is the address to send change to, defaults to the PaymentPk address
+	ChangeAddress string
+	// SatsPerKb is the fee rate in satoshis per kilobyte
+	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
+}
+
+// BuildOperations builds one transaction combining several operations
+// A purchased listing and its payout come first, then ordinals being sent or listed so each keeps its satoshi,
+// followed by inscriptions, token transfers, payments, burns and change.
+// Before signing, the satoshis of every ordinal and the amount of every token are
+// checked to be conserved, otherwise an error wrapping ErrConservation is returned.
+func BuildOperations(config *OperationsConfig) (*transaction.Transaction, error) {
+	b := newTxBuilder(false)
+	if err := buildOperations(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.tx, nil
+}
+
+// trackedOrdinal is an ordinal input and whether it is being burned
+type trackedOrdinal struct {
+	input *transaction.TransactionInput
+	burn  bool
+}
+
+// buildOperations adds the inputs and outputs of BuildOperations to the builder
+func buildOperations(b *Builder, config *OperationsConfig) error {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
+
+	if len(config.Operations) == 0 {
+		return fmt.Errorf("at least one operation is required")
+	}
+
+	var ordinals []trackedOrdinal
+	tokensIn := make(map[string]uint64)
+
+	// trackLastInput records the last input added as an ordinal
+	trackLastInput := func(burn bool) {
+		ordinals = append(ordinals, trackedOrdinal{input: b.tx.Inputs[len(b.tx.Inputs)-1], burn: burn})
+	}
+
+	// A purchase is the first input and output, followed by the payout of the listing.
+	// The satoshi of any other ordinal being kept would land on the payout.
+	var purchase *PurchaseOperation
+	keepsOrdinals := false
+	for _, op := range config.Operations {
+		switch op := op.(type) {
+		case *PurchaseOperation:
+			if purchase != nil {
+				return fmt.Errorf("%w: only one listing can be purchased per transaction", ErrConservation)
+			}
+			purchase = op
+		case *SendOrdinalOperation, *ListOperation:
+			keepsOrdinals = true
+		}
+	}
+	if purchase != nil && keepsOrdinals {
+		return fmt.Errorf("%w: a purchase can't be combined with sending or listing ordinals", ErrConservation)
+	}
+
+	// The purchased ordinal goes to output 0 and the payout from the listing to output 1
+	if purchase != nil {
+		err := b.AddOrdLockPurchase(purchase.Listing, &Destination{Address: purchase.OrdAddress})
+		if err != nil {
+			return fmt.Errorf("failed to purchase listing: %w", err)
+		}
+		trackLastInput(false)
+	}
+
+	// Add the ordinals that keep their satoshi, each input is followed by its output
+	for _, op := range config.Operations {
+		switch op := op.(type) {
+		case *SendOrdinalOperation:
+			err := b.AddOrdinal(op.Ordinal, config.OrdPk, op.Destination)
+			if err != nil {
+				return fmt.Errorf("failed to send ordinal: %w", err)
+			}
+			trackLastInput(false)
+
+		case *ListOperation:
+			err := b.AddOrdLock(op.Ordinal, config.OrdPk, op.SellerAddress, op.PayAddress, op.Price)
+			if err != nil {
+				return fmt.Errorf("failed to list ordinal: %w", err)
+			}
+			trackLastInput(false)
+		}
+	}
+
+	// Add new inscriptions
+	for _, op := range config.Operations {
+		if op, ok := op.(*InscribeOperation); ok {
+			err := b.AddInscription(op.Destination)
+			if err != nil {
+				return fmt.Errorf("failed to inscribe: %w", err)
+			}
+		}
+	}
+
+	// Add token transfers
+	for _, op := range config.Operations {
+		if op, ok := op.(*TransferTokensOperation); ok {
+			// Tokens sent without metadata can't be tracked, the conservation check would count them as lost
+			for i, dist := range op.Distributions {
+				if dist.OmitMetadata {
+					return fmt.Errorf("%w: distribution %d of token %s omits metadata", ErrConservation, i, op.TokenID)
+				}
+			}
+
+			spent, _, err := b.AddTokenTransfer(&TransferBsv21TokenConfig{
+				Protocol:           TokenTypeBSV21,
+				TokenID:            op.TokenID,
+				InputTokens:        op.InputTokens,
+				Distributions:      op.Distributions,
+				OrdPk:              config.OrdPk,
+				BurnRemaining:      op.BurnRemaining,
+				TokenChangeAddress: op.TokenChangeAddress,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to transfer tokens: %w", err)
+			}
+
+			for _, token := range spent {
+				tokensIn[token.TokenID] += token.Amount
+			}
+		}
+	}
+
+	// Add payments
+	for _, op := range config.Operations {
+		if op, ok := op.(*PayOperation); ok {
+			err := b.AddPayment(op.Address, op.Satoshis)
+			if err != nil {
+				return fmt.Errorf("failed to add payment: %w", err)
+			}
+		}
+	}
+
+	// Add burn outputs
+	var burned []*NftUtxo
+	for _, op := range config.Operations {
+		if op, ok := op.(*BurnOperation); ok {
+			var data [][]byte
+			if len(op.Metadata) > 0 {
+				data = mapOpReturnData(op.Metadata)
+			}
+
+			err := b.AddOpReturn(data...)
+			if err != nil {
+				return fmt.Errorf("failed to burn ordinals: %w", err)
+			}
+			burned = append(burned, op.Ordinals...)
+		}
+	}
+
+	// Add payment inputs
+	err := b.AddPaymentUtxos(config.Utxos, config.PaymentPk)
+	if err != nil {
+		return err
+	}
+
+	// Burned ordinals are spent last so their satoshis are paid as fee
+	for _, ordinal := range burned {
+		err = b.SpendOrdinal(ordinal, config.OrdPk)
+		if err != nil {
+			return fmt.Errorf("failed to burn ordinal: %w", err)
+		}
+		trackLastInput(true)
+	}
+
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return fmt.Errorf("failed to add change output: %w", err)
+	}
+
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
+
+	err = b.Fee(feeModel)
+	if err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return fmt.Errorf("not enough funds to build operations: %w", err)
+		}
+		return fmt.Errorf("failed to calculate fee: %w", err)
+	}
+
+	// Check nothing is lost before signing
+	err = validateConservation(b, ordinals, tokensIn)
+	if err != nil {
+		return err
+	}
+
+	// Sign the transaction
+	_, err = b.Sign()
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return nil
+}
+
+// validateConservation checks that no satoshis, ordinals or tokens are lost
+// Ordinals follow their satoshi from the inputs to the outputs in order. A kept ordinal
+// must land on a 1 sat ordinal or listing output and a burned one must be paid as fee.
+// The tokens of every ID in the outputs, transferred or burned, must equal the inputs.
+func validateConservation(b *Builder, ordinals []trackedOrdinal, tokensIn map[string]uint64) error {
+	// Satoshis
+	totalIn, err := b.tx.TotalInputSatoshis()
+	if err != nil {
+		return fmt.Errorf("failed to total inputs: %w", err)
+	}
+	totalOut := b.tx.TotalOutputSatoshis()
+	if totalOut > totalIn {
+		return fmt.Errorf("%w: outputs of %d satoshis exceed inputs of %d", ErrConservation, totalOut, totalIn)
+	}
+
+	// Ordinals
+	for _, ordinal := range ordinals {
+		offset := inputOffset(b.tx, ordinal.input)
+		output := outputAtOffset(b.tx, offset)
+		outpoint := fmt.Sprintf("%s_%d", ordinal.input.SourceTXID, ordinal.input.SourceTxOutIndex)
+
+		if ordinal.burn {
+			if output != nil {
+				return fmt.Errorf("%w: burned ordinal %s would land in output with role %s", ErrConservation, outpoint, b.outputRoles[output])
+			}
+			continue
+		}
+
+		if output == nil {
+			return fmt.Errorf("%w: ordinal %s would be paid as fee", ErrConservation, outpoint)
+		}
+		role := b.outputRoles[output]
+		if output.Satoshis != 1 || (role != TxRoleOrdinal && role != TxRoleListing) {
+			return fmt.Errorf("%w: ordinal %s would land in a %d satoshi output with role %s", ErrConservation, outpoint, output.Satoshis, role)
+		}
+	}
+
+	// Tokens
+	tokensOut := make(map[string]uint64)
+	for _, output := range b.tx.Outputs {
+		token := decodeBsv21(output.LockingScript)
+		if token == nil || token.Id == "" {
+			continue
+		}
+		tokensOut[token.Id] += token.Amt
+	}
+
+	for tokenID, amount := range tokensIn {
+		if tokensOut[tokenID] != amount {
+			return fmt.Errorf("%w: token %s has %d in inputs and %d in outputs", ErrConservation, tokenID, amount, tokensOut[tokenID])
+		}
+	}
+	for tokenID, amount := range tokensOut {
+		if _, ok := tokensIn[tokenID]; !ok {
+			return fmt.Errorf("%w: token %s has %d in outputs and none in inputs", ErrConservation, tokenID, amount)
+		}
+	}
+
+	return nil
+}
+
+// inputOffset returns the position of the first satoshi of an input among all input satoshis
+func inputOffset(tx *transaction.Transaction, target *transaction.TransactionInput) uint64 {
+	var offset uint64
+	for _, input := range tx.Inputs {
+		if input == target {
+			break
+		}
+		if sats := input.SourceTxSatoshis(); sats != nil {
+			offset += *sats
+		}
+	}
+
+	return offset
+}
+
+// outputAtOffset returns the output holding the satoshi at offset, or nil if it is paid as fee
+func outputAtOffset(tx *transaction.Transaction, offset uint64) *transaction.TransactionOutput {
+	vout := outputIndexAtOffset(tx, offset)
+	if vout < 0 {
+		return nil
+	}
+
+	return tx.Outputs[vout]
+}
+
+// outputIndexAtOffset returns the index of the output holding the satoshi at offset, or -1 if it is paid as fee
+func outputIndexAtOffset(tx *transaction.Transaction, offset uint64) int {
+	var start uint64
+	for vout, output := range tx.Outputs {
+		if offset >= start && offset < start+output.Satoshis {
+			return vout
+		}
+		start += output.Satoshis
+	}
+
+	return -1
+}
@@ -0,0 +1,259 @@
+package ordinals
+
+import (
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildOperations tests combining several operations in one transaction
+func TestBuildOperations(t *testing.T) {
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// nftUtxo returns a 1 sat ordinal at the given vout
+	nftUtxo := func(vout uint32) *NftUtxo {
+		return &NftUtxo{
+			Utxo: Utxo{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+				Vout:         vout,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     1,
+			},
+		}
+	}
+
+	// transfer returns a token transfer of 400 of 1000 tokens
+	transfer := func() *TransferTokensOperation {
+		return &TransferTokensOperation{
+			TokenID: tokenID,
+			InputTokens: []*TokenUtxo{{
+				Utxo: Utxo{
+					TxID:         "0000000000000000000000000000000000000000000000000000000000000005",
+					Vout:         0,
+					ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+					Satoshis:     1,
+				},
+				TokenID:  tokenID,
+				Protocol: TokenTypeBSV21,
+				Amount:   1000,
+			}},
+			Distributions: []*TokenDistribution{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Tokens: 400},
+			},
+		}
+	}
+
+	// newConfig returns a config funding the operations
+	newConfig := func(operations ...Operation) *OperationsConfig {
+		return &OperationsConfig{
+			Utxos: []*Utxo{{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+				Vout:         0,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     100000,
+			}},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+			Operations:    operations,
+			ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		}
+	}
+
+	// outputRoles returns the role of each output
+	outputRoles := func(plan *TxPlan) []TxRole {
+		roles := make([]TxRole, 0, len(plan.Outputs))
+		for _, output := range plan.Outputs {
+			roles = append(roles, output.Role)
+		}
+		return roles
+	}
+
+	t.Run("checkout", func(t *testing.T) {
+		// Operations are given in any order, ordinals are placed first
+		config := newConfig(
+			&PayOperation{Address: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA", Satoshis: 1000},
+			&InscribeOperation{Destination: &Destination{
+				Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+				Inscription: &inscription.Inscription{
+					File: inscription.File{Content: []byte("receipt"), Type: "text/plain"},
+				},
+			}},
+			transfer(),
+			&SendOrdinalOperation{Ordinal: nftUtxo(0), Destination: &Destination{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"}},
+		)
+
+		tx, err := BuildOperations(config)
+		require.NoError(t, err)
+		for _, input := range tx.Inputs {
+			assert.NotNil(t, input.UnlockingScript)
+		}
+
+		plan, err := EstimateOperations(config)
+		require.NoError(t, err)
+		assert.Equal(t, []TxRole{
+			TxRoleOrdinal, TxRoleInscription, TxRoleToken, TxRoleTokenChange, TxRolePayment, TxRoleChange,
+		}, outputRoles(plan))
+		assert.Equal(t, TxRoleOrdinal, plan.Inputs[0].Role)
+		assert.Equal(t, TxRoleToken, plan.Inputs[1].Role)
+		assert.Equal(t, TxRolePayment, plan.Inputs[2].Role)
+	})
+
+	t.Run("list and burn", func(t *testing.T) {
+		config := newConfig(
+			&BurnOperation{Ordinals: []*NftUtxo{nftUtxo(2)}, Metadata: map[string][]byte{"app": []byte("test")}},
+			&ListOperation{
+				Ordinal:       nftUtxo(0),
+				SellerAddress: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+				PayAddress:    "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+				Price:         5000,
+			},
+		)
+
+		plan, err := EstimateOperations(config)
+		require.NoError(t, err)
+		assert.Equal(t, []TxRole{TxRoleListing, TxRoleOpReturn, TxRoleChange}, outputRoles(plan))
+
+		// The burned ordinal is spent last and paid as fee
+		last := plan.Inputs[len(plan.Inputs)-1]
+		assert.Equal(t, uint32(2), last.Vout)
+		assert.Equal(t, TxRoleOrdinal, last.Role)
+
+		tx, err := BuildOperations(config)
+		require.NoError(t, err)
+
+		// The listing output is an Ordinal Lock
+		listing := ParseTransaction(tx).Outputs[0]
+		assert.Equal(t, OutputKindOrdLock, listing.Kind)
+		assert.Equal(t, uint64(5000), listing.Listing.Price)
+	})
+
+	t.Run("purchase", func(t *testing.T) {
+		purchase := &PurchaseOperation{
+			Listing:    listOrdinal(t, "1BitcoinEaterAddressDontSendf59kuE", "1BitcoinEaterAddressDontSendf59kuE", 2000),
+			OrdAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		}
+		config := newConfig(
+			&PayOperation{Address: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA", Satoshis: 1000},
+			purchase,
+		)
+
+		// The payout decoded from the listing follows the purchased ordinal
+		plan, err := EstimateOperations(config)
+		require.NoError(t, err)
+		assert.Equal(t, []TxRole{
+			TxRoleOrdinal, TxRolePayment, TxRolePayment, TxRoleChange,
+		}, outputRoles(plan))
+		assert.Equal(t, TxRoleListing, plan.Inputs[0].Role)
+		assert.Equal(t, uint64(2000), plan.Outputs[1].Satoshis)
+
+		tx, err := BuildOperations(config)
+		require.NoError(t, err)
+		verifyInput(t, tx, 0)
+
+		// Only one listing can be purchased, and no other ordinal can keep its satoshi
+		config = newConfig(purchase, &SendOrdinalOperation{
+			Ordinal:     nftUtxo(0),
+			Destination: &Destination{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"},
+		})
+		_, err = BuildOperations(config)
+		assert.ErrorIs(t, err, ErrConservation)
+		assert.ErrorIs(t, Validate(config), ErrConservation)
+	})
+
+	t.Run("tokens sent without metadata are rejected", func(t *testing.T) {
+		op := transfer()
+		op.Distributions[0].OmitMetadata = true
+
+		_, err := BuildOperations(newConfig(op))
+		assert.ErrorIs(t, err, ErrConservation)
+		assert.Contains(t, err.Error(), "omits metadata")
+
+		err = Validate(newConfig(op))
+		assert.ErrorIs(t, err, ErrConservation)
+	})
+
+	t.Run("burning the remaining tokens", func(t *testing.T) {
+		op := transfer()
+		op.BurnRemaining = true
+
+		_, err := BuildOperations(newConfig(op))
+		assert.NoError(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := BuildOperations(newConfig())
+		assert.Error(t, err)
+
+		notOneSat := nftUtxo(0)
+		notOneSat.Satoshis = 2
+		_, err = BuildOperations(newConfig(&SendOrdinalOperation{
+			Ordinal:     notOneSat,
+			Destination: &Destination{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"},
+		}))
+		assert.ErrorIs(t, err, ErrOrdinalNotOneSat)
+
+		_, err = BuildOperations(newConfig(&PayOperation{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 200000}))
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+}
+
+// TestValidateConservation tests the ordinal and token checks
+func TestValidateConservation(t *testing.T) {
+	ordinal := &NftUtxo{
+		Utxo: Utxo{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     1,
+		},
+	}
+	payment := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}
+
+	t.Run("ordinal lands in change", func(t *testing.T) {
+		// The payment input comes first so the ordinal's satoshi ends up in the change
+		b := newTxBuilder(true)
+		require.NoError(t, b.AddPaymentUtxos([]*Utxo{payment}, nil))
+		require.NoError(t, b.SpendOrdinal(ordinal, nil))
+		ordinalInput := b.tx.Inputs[1]
+		require.NoError(t, b.AddOrdinalOutput(&Destination{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"}))
+		require.NoError(t, b.Change("1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA", nil))
+		require.NoError(t, b.Fee(nil))
+
+		err := validateConservation(b, []trackedOrdinal{{input: ordinalInput}}, nil)
+		assert.ErrorIs(t, err, ErrConservation)
+	})
+
+	t.Run("tokens without inputs", func(t *testing.T) {
+		b := newTxBuilder(true)
+		_, _, err := b.AddTokenTransfer(&TransferBsv21TokenConfig{
+			Protocol: TokenTypeBSV21,
+			TokenID:  "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0",
+			InputTokens: []*TokenUtxo{{
+				Utxo:     ordinal.Utxo,
+				TokenID:  "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0",
+				Protocol: TokenTypeBSV21,
+				Amount:   10,
+			}},
+			Distributions: []*TokenDistribution{
+				{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Tokens: 10},
+			},
+		})
+		require.NoError(t, err)
+
+		err = validateConservation(b, nil, map[string]uint64{})
+		assert.ErrorIs(t, err, ErrConservation)
+	})
+}
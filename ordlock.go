@@ -1,359 +1,300 @@
 package ordinals
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/bitcoin-sv/go-templates/template/ordlock"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/bsv-blockchain/go-sdk/transaction"
-	fee_model "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
 	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
 )
 
 // CreateOrdListings creates a listing using an "Ordinal Lock" script
 func CreateOrdListings(config *CreateOrdListingsConfig) (*transaction.Transaction, error) {
-	// Create a new transaction
-	tx := transaction.NewTransaction()
+	b := newTxBuilder(false)
+	if err := buildCreateOrdListings(b, config); err != nil {
+		return nil, err
+	}
 
-	// Add inputs
-	for _, utxo := range config.Utxos {
-		unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create unlocker: %w", err)
-		}
+	return b.tx, nil
+}
 
-		err = tx.AddInputFrom(
-			utxo.TxID,
-			utxo.Vout,
-			utxo.ScriptPubKey,
-			utxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add input: %w", err)
-		}
+// buildCreateOrdListings adds the inputs and outputs of CreateOrdListings to the builder
+func buildCreateOrdListings(b *Builder, config *CreateOrdListingsConfig) error {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
+
+	// Add inputs
+	err := b.AddPaymentUtxos(config.Utxos, config.PaymentPk)
+	if err != nil {
+		return err
 	}
 
-	// Add ordinal input (for each listing)
+	// Add ordinal input and listing output (for each listing)
 	for _, listing := range config.Listings {
-		ordUtxo := listing.ListingUtxo
-
-		unlocker, err := p2pkh.Unlock(config.OrdPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create ordinal unlocker: %w", err)
-		}
-
-		err = tx.AddInputFrom(
-			ordUtxo.TxID,
-			ordUtxo.Vout,
-			ordUtxo.ScriptPubKey,
-			ordUtxo.Satoshis,
-			unlocker,
-		)
+		err = b.AddOrdLock(listing.ListingUtxo, config.OrdPk, listing.OrdAddress, listing.PayAddress, listing.Price)
 		if err != nil {
-			return nil, fmt.Errorf("failed to add ordinal input: %w", err)
+			return err
 		}
+	}
 
-		// Create seller address (for return on cancel)
-		sellerAddr, err := script.NewAddressFromString(listing.OrdAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create seller address: %w", err)
-		}
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return fmt.Errorf("failed to add change output: %w", err)
+	}
 
-		// Create pay address (where payment is sent)
-		payAddr, err := script.NewAddressFromString(listing.PayAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create pay address: %w", err)
-		}
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
 
-		// Create P2PKH script for payment
-		paymentScript, err := p2pkh.Lock(payAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create payment script: %w", err)
+	err = b.Fee(feeModel)
+	if err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return fmt.Errorf("not enough funds to create listings: %w", err)
 		}
+		return fmt.Errorf("failed to calculate fee: %w", err)
+	}
 
-		// Create the output for payment recipient
-		payOutput := &transaction.TransactionOutput{
-			LockingScript: paymentScript,
-			Satoshis:      listing.Price,
-		}
+	// Sign the transaction
+	_, err = b.Sign()
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
 
-		// Create the OrdLock
-		// Note: This is just placeholder code for future implementation
-		_ = ordlock.OrdLock{
-			Seller: sellerAddr,
-			Price:  listing.Price,
-			PayOut: payOutput.Bytes(),
-		}
+	return nil
+}
 
-		// TODO: Create the actual OrdLock script
-		// We need to use the go-templates ordlock package to create the script
-		// This is a bit more complex than just creating a simple script
+// ordLockScript creates an Ordinal Lock locking script
+// It can be cancelled by sellerAddr, or purchased by a transaction paying price satoshis to payAddr.
+func ordLockScript(sellerAddr, payAddr *script.Address, price uint64) (*script.Script, error) {
+	paymentScript, err := p2pkh.Lock(payAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment script: %w", err)
+	}
 
-		// For now, we'll just use a P2PKH script as a placeholder
-		// In a proper implementation, you'd use the ordlock template to create the script
-		lockingScript, err := p2pkh.Lock(sellerAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create locking script: %w", err)
-		}
+	payOutput := &transaction.TransactionOutput{
+		LockingScript: paymentScript,
+		Satoshis:      price,
+	}
 
-		// Add the output to the transaction
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: lockingScript,
-			Satoshis:      1, // 1 sat for ordinals
-		})
+	// The script is the prefix, the seller key hash, the serialized payment output and the suffix
+	lockingScript := script.NewFromBytes(append([]byte{}, ordlock.OrdLockPrefix...))
+	if err = lockingScript.AppendPushData(sellerAddr.PublicKeyHash); err != nil {
+		return nil, fmt.Errorf("failed to create ord lock script: %w", err)
+	}
+	if err = lockingScript.AppendPushData(payOutput.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to create ord lock script: %w", err)
 	}
+	*lockingScript = append(*lockingScript, ordlock.OrdLockSuffix...)
 
-	// Add change output if needed
-	if config.ChangeAddress != "" {
-		changeAddr, err := script.NewAddressFromString(config.ChangeAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change address: %w", err)
-		}
+	return lockingScript, nil
+}
 
-		changeScript, err := p2pkh.Lock(changeAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change script: %w", err)
-		}
+// ordLockPurchase is an unlocking template for purchasing an Ordinal Lock listing
+// The script checks the outputs against a sighash preimage: output 0 is the buyer's,
+// output 1 must be the payout of the listing and any other outputs follow.
+type ordLockPurchase struct{}
 
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: changeScript,
-			Change:        true,
-		})
+// Sign pushes output 0, the outputs after the payout, the preimage and selects the purchase branch
+func (u *ordLockPurchase) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if len(tx.Outputs) < 2 {
+		return nil, fmt.Errorf("purchase needs the buyer and payout outputs, have %d outputs", len(tx.Outputs))
 	}
 
-	// Set fee rate using SatsPerKb if provided, otherwise use the default value
-	feeRate := uint64(DEFAULT_SAT_PER_KB)
-	if config.SatsPerKb > 0 {
-		feeRate = config.SatsPerKb
+	preimage, err := tx.CalcInputPreimage(inputIndex, sighash.AllForkID|sighash.AnyOneCanPay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate preimage: %w", err)
 	}
 
-	// Create fee model for computation
-	feeModel := &fee_model.SatoshisPerKilobyte{
-		Satoshis: feeRate,
+	unlockingScript := &script.Script{}
+	if err = unlockingScript.AppendPushData(tx.Outputs[0].Bytes()); err != nil {
+		return nil, err
 	}
 
-	err := tx.Fee(feeModel, transaction.ChangeDistributionEqual)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate fee: %w", err)
+	if len(tx.Outputs) > 2 {
+		var trailing []byte
+		for _, output := range tx.Outputs[2:] {
+			trailing = append(trailing, output.Bytes()...)
+		}
+		if err = unlockingScript.AppendPushData(trailing); err != nil {
+			return nil, err
+		}
+	} else if err = unlockingScript.AppendOpcodes(script.Op0); err != nil {
+		return nil, err
 	}
 
-	// Sign the transaction
-	err = tx.Sign()
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	if err = unlockingScript.AppendPushData(preimage); err != nil {
+		return nil, err
+	}
+	if err = unlockingScript.AppendOpcodes(script.Op0); err != nil {
+		return nil, err
 	}
 
-	return tx, nil
+	return unlockingScript, nil
 }
 
-// PurchaseOrdListing purchases an Ordinal Lock listing
-func PurchaseOrdListing(config *PurchaseOrdListingConfig) (*transaction.Transaction, error) {
-	// Create a new transaction
-	tx := transaction.NewTransaction()
-
-	// Add inputs
-	for _, utxo := range config.Utxos {
-		unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create unlocker: %w", err)
-		}
-
-		err = tx.AddInputFrom(
-			utxo.TxID,
-			utxo.Vout,
-			utxo.ScriptPubKey,
-			utxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add input: %w", err)
-		}
+// EstimateLength returns the size of the unlocking script for the current outputs
+// It doesn't depend on any key, so it is exact once the outputs are in place.
+func (u *ordLockPurchase) EstimateLength(tx *transaction.Transaction, inputIndex uint32) uint32 {
+	unlockingScript, err := u.Sign(tx, inputIndex)
+	if err != nil {
+		return 0
 	}
 
-	// Add the ordinal listing input
-	ordUtxo := config.ListingUtxo
+	return uint32(len(*unlockingScript))
+}
 
-	// TODO: Implement the proper unlocking for OrdLock
-	// This would require implementing a custom unlocker
-	// For now, we'll use a simple P2PKH unlocker as a placeholder
-	unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create ordinal unlocker: %w", err)
-	}
+// ordLockCancel is an unlocking template for the seller cancelling an Ordinal Lock listing
+type ordLockCancel struct {
+	key *ec.PrivateKey
+}
 
-	err = tx.AddInputFrom(
-		ordUtxo.TxID,
-		ordUtxo.Vout,
-		ordUtxo.ScriptPubKey,
-		ordUtxo.Satoshis,
-		unlocker,
-	)
+// Sign pushes the seller's signature and public key and selects the cancel branch
+func (u *ordLockCancel) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	unlocker, err := p2pkh.Unlock(u.key, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add ordinal input: %w", err)
+		return nil, err
 	}
 
-	// Create output for the ordinal
-	dstAddr, err := script.NewAddressFromString(config.OrdAddress)
+	unlockingScript, err := unlocker.Sign(tx, inputIndex)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create destination address: %w", err)
+		return nil, err
 	}
 
-	lockingScript, err := p2pkh.Lock(dstAddr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
+	if err = unlockingScript.AppendOpcodes(script.Op1); err != nil {
+		return nil, err
 	}
 
-	tx.AddOutput(&transaction.TransactionOutput{
-		LockingScript: lockingScript,
-		Satoshis:      1, // 1 sat for ordinals
-	})
+	return unlockingScript, nil
+}
 
-	// Add change output if needed
-	if config.ChangeAddress != "" {
-		changeAddr, err := script.NewAddressFromString(config.ChangeAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change address: %w", err)
-		}
+// EstimateLength returns the size of a P2PKH unlocking script and the branch selector
+func (u *ordLockCancel) EstimateLength(_ *transaction.Transaction, _ uint32) uint32 {
+	return P2PKH_UNLOCKING_SCRIPT_SIZE + 1
+}
 
-		changeScript, err := p2pkh.Lock(changeAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change script: %w", err)
-		}
+// PurchaseOrdListing purchases an Ordinal Lock listing
+func PurchaseOrdListing(config *PurchaseOrdListingConfig) (*transaction.Transaction, error) {
+	b := newTxBuilder(false)
+	if err := buildPurchaseOrdListing(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.tx, nil
+}
+
+// buildPurchaseOrdListing adds the inputs and outputs of PurchaseOrdListing to the builder
+func buildPurchaseOrdListing(b *Builder, config *PurchaseOrdListingConfig) error {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
 
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: changeScript,
-			Change:        true,
-		})
+	// Add the listing input, the ordinal output and the payout to the seller
+	err := b.AddOrdLockPurchase(config.ListingUtxo, &Destination{Address: config.OrdAddress})
+	if err != nil {
+		return fmt.Errorf("failed to purchase listing: %w", err)
 	}
 
-	// Set fee rate using SatsPerKb if provided, otherwise use the default value
-	feeRate := uint64(DEFAULT_SAT_PER_KB)
-	if config.SatsPerKb > 0 {
-		feeRate = config.SatsPerKb
+	// Add inputs
+	err = b.AddPaymentUtxos(config.Utxos, config.PaymentPk)
+	if err != nil {
+		return err
 	}
 
-	// Create fee model for computation
-	feeModel := &fee_model.SatoshisPerKilobyte{
-		Satoshis: feeRate,
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return fmt.Errorf("failed to add change output: %w", err)
 	}
 
-	err = tx.Fee(feeModel, transaction.ChangeDistributionEqual)
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
+
+	err = b.Fee(feeModel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate fee: %w", err)
+		if errors.Is(err, ErrInsufficientFunds) {
+			return fmt.Errorf("not enough funds to purchase listing: %w", err)
+		}
+		return fmt.Errorf("failed to calculate fee: %w", err)
 	}
 
 	// Sign the transaction
-	err = tx.Sign()
+	_, err = b.Sign()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	return tx, nil
+	return nil
 }
 
 // CancelOrdListings cancels an Ordinal Lock listing and returns the ordinal
 func CancelOrdListings(config *CancelOrdListingsConfig) (*transaction.Transaction, error) {
-	// Create a new transaction
-	tx := transaction.NewTransaction()
-
-	// Add inputs
-	for _, utxo := range config.Utxos {
-		unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create unlocker: %w", err)
-		}
-
-		err = tx.AddInputFrom(
-			utxo.TxID,
-			utxo.Vout,
-			utxo.ScriptPubKey,
-			utxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add input: %w", err)
-		}
+	b := newTxBuilder(false)
+	if err := buildCancelOrdListings(b, config); err != nil {
+		return nil, err
 	}
 
-	// Add the ordinal listing inputs
-	for _, listingUtxo := range config.ListingUtxos {
-		// TODO: Implement the proper unlocking for OrdLock (cancel path)
-		// This would require implementing a custom unlocker
-		// For now, we'll use a simple P2PKH unlocker as a placeholder
-		unlocker, err := p2pkh.Unlock(config.OrdPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create ordinal unlocker: %w", err)
-		}
+	return b.tx, nil
+}
 
-		err = tx.AddInputFrom(
-			listingUtxo.TxID,
-			listingUtxo.Vout,
-			listingUtxo.ScriptPubKey,
-			listingUtxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add ordinal input: %w", err)
-		}
+// buildCancelOrdListings adds the inputs and outputs of CancelOrdListings to the builder
+func buildCancelOrdListings(b *Builder, config *CancelOrdListingsConfig) error {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
 
-		// Create output returning the ordinal to the original owner
-		// Derive destination from OrdPk
-		dstAddr, err := script.NewAddressFromPublicKey(config.OrdPk.PubKey(), true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create destination address: %w", err)
+	// Add the ordinal listing inputs first, so each ordinal lands in its returned output
+	for _, listingUtxo := range config.ListingUtxos {
+		// Return the ordinal to the original owner
+		// Derive destination from OrdPk, a missing key uses a placeholder of the same size and fails to sign
+		lockingScript := placeholderScript()
+		if config.OrdPk != nil {
+			dstAddr, err := script.NewAddressFromPublicKey(config.OrdPk.PubKey(), true)
+			if err != nil {
+				return fmt.Errorf("failed to create destination address: %w", err)
+			}
+
+			lockingScript, err = p2pkh.Lock(dstAddr)
+			if err != nil {
+				return fmt.Errorf("failed to create p2pkh script: %w", err)
+			}
 		}
 
-		lockingScript, err := p2pkh.Lock(dstAddr)
+		err := b.cancelOrdLock(&listingUtxo.Utxo, config.OrdPk, TxRoleOrdinal, lockingScript)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
+			return fmt.Errorf("failed to cancel listing: %w", err)
 		}
-
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: lockingScript,
-			Satoshis:      1, // 1 sat for ordinals
-		})
 	}
 
-	// Add change output if needed
-	if config.ChangeAddress != "" {
-		changeAddr, err := script.NewAddressFromString(config.ChangeAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change address: %w", err)
-		}
-
-		changeScript, err := p2pkh.Lock(changeAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change script: %w", err)
-		}
-
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: changeScript,
-			Change:        true,
-		})
+	// Add inputs
+	err := b.AddPaymentUtxos(config.Utxos, config.PaymentPk)
+	if err != nil {
+		return err
 	}
 
-	// Set fee rate using SatsPerKb if provided, otherwise use the default value
-	feeRate := uint64(DEFAULT_SAT_PER_KB)
-	if config.SatsPerKb > 0 {
-		feeRate = config.SatsPerKb
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return fmt.Errorf("failed to add change output: %w", err)
 	}
 
-	// Create fee model for computation
-	feeModel := &fee_model.SatoshisPerKilobyte{
-		Satoshis: feeRate,
-	}
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
 
-	err := tx.Fee(feeModel, transaction.ChangeDistributionEqual)
+	err = b.Fee(feeModel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate fee: %w", err)
+		if errors.Is(err, ErrInsufficientFunds) {
+			return fmt.Errorf("not enough funds to cancel listings: %w", err)
+		}
+		return fmt.Errorf("failed to calculate fee: %w", err)
 	}
 
 	// Sign the transaction
-	err = tx.Sign()
+	_, err = b.Sign()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	return tx, nil
+	return nil
 }
@@ -0,0 +1,187 @@
+package ordinals
+
+import (
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// TxLoader loads a transaction by ID, e.g. from a local store or a block explorer
+type TxLoader func(txid string) (*transaction.Transaction, error)
+
+// OrdinalTrace describes how the 1 sat ordinals of a transaction move from its inputs to its outputs
+type OrdinalTrace struct {
+	// Transfers describes each 1 sat input, in input order
+	Transfers []*OrdinalTransfer
+	// Origins maps the index of each 1 sat output to the origin outpoint of its ordinal
+	Origins map[uint32]string
+}
+
+// OrdinalTransfer describes where the ordinal of a 1 sat input lands
+type OrdinalTransfer struct {
+	// Input is the index of the input
+	Input int
+	// Outpoint is the outpoint spent by the input
+	Outpoint string
+	// Origin is the outpoint the ordinal was first created in
+	Origin string
+	// Output is the index of the output the ordinal lands in, -1 if it is paid as fee
+	Output int
+	// Burned is true if the ordinal is paid as fee or lands in an output that isn't 1 sat
+	Burned bool
+}
+
+// TraceOrdinals follows the 1Sat ordinal position rules through a transaction
+// Satoshis are ordered across the inputs and then across the outputs, an ordinal is the first
+// satoshi of a 1 sat output. Source transactions are loaded with load to find the value of each
+// input and to follow each ordinal back to its origin: the first 1 sat output its satoshi landed in.
+func TraceOrdinals(tx *transaction.Transaction, load TxLoader) (*OrdinalTrace, error) {
+	t := &ordinalTracer{load: load, txs: make(map[string]*transaction.Transaction)}
+
+	inputSats, err := t.inputSatoshis(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &OrdinalTrace{Origins: make(map[uint32]string)}
+
+	// Follow each 1 sat input to the output holding its satoshi
+	var offset uint64
+	inputOrigins := make(map[int]string)
+	for i, input := range tx.Inputs {
+		if inputSats[i] == 1 {
+			origin, err := t.origin(input.SourceTXID.String(), input.SourceTxOutIndex)
+			if err != nil {
+				return nil, err
+			}
+			inputOrigins[i] = origin
+
+			vout := outputIndexAtOffset(tx, offset)
+			trace.Transfers = append(trace.Transfers, &OrdinalTransfer{
+				Input:    i,
+				Outpoint: fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex),
+				Origin:   origin,
+				Output:   vout,
+				Burned:   vout < 0 || tx.Outputs[vout].Satoshis != 1,
+			})
+		}
+		offset += inputSats[i]
+	}
+
+	// Each 1 sat output inherits the origin of a 1 sat input landing in it, otherwise it is a new origin
+	txid := tx.TxID().String()
+	offset = 0
+	for vout, output := range tx.Outputs {
+		if output.Satoshis == 1 {
+			origin := fmt.Sprintf("%s_%d", txid, vout)
+			if i := inputIndexAtOffset(inputSats, offset); i >= 0 && inputSats[i] == 1 {
+				origin = inputOrigins[i]
+			}
+			trace.Origins[uint32(vout)] = origin
+		}
+		offset += output.Satoshis
+	}
+
+	return trace, nil
+}
+
+// OrdinalOrigin returns the origin outpoint of the ordinal in a 1 sat output
+// It returns "" if the output isn't 1 sat and so holds no ordinal.
+func OrdinalOrigin(txid string, vout uint32, load TxLoader) (string, error) {
+	t := &ordinalTracer{load: load, txs: make(map[string]*transaction.Transaction)}
+	return t.origin(txid, vout)
+}
+
+// ordinalTracer loads and caches the transactions needed to trace ordinals
+type ordinalTracer struct {
+	load TxLoader
+	txs  map[string]*transaction.Transaction
+}
+
+// transaction loads a transaction once
+func (t *ordinalTracer) transaction(txid string) (*transaction.Transaction, error) {
+	if tx, ok := t.txs[txid]; ok {
+		return tx, nil
+	}
+
+	tx, err := t.load(txid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transaction %s: %w", txid, err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", txid)
+	}
+
+	t.txs[txid] = tx
+	return tx, nil
+}
+
+// inputSatoshis returns the value of each input, loading the source transactions that aren't attached
+func (t *ordinalTracer) inputSatoshis(tx *transaction.Transaction) ([]uint64, error) {
+	sats := make([]uint64, len(tx.Inputs))
+	for i, input := range tx.Inputs {
+		if sourceSats := input.SourceTxSatoshis(); sourceSats != nil {
+			sats[i] = *sourceSats
+			continue
+		}
+
+		sourceTx, err := t.transaction(input.SourceTXID.String())
+		if err != nil {
+			return nil, err
+		}
+		if int(input.SourceTxOutIndex) >= len(sourceTx.Outputs) {
+			return nil, fmt.Errorf("output %d not found in transaction %s", input.SourceTxOutIndex, input.SourceTXID.String())
+		}
+		sats[i] = sourceTx.Outputs[input.SourceTxOutIndex].Satoshis
+	}
+
+	return sats, nil
+}
+
+// origin walks back from a 1 sat output to the first 1 sat output its satoshi landed in
+func (t *ordinalTracer) origin(txid string, vout uint32) (string, error) {
+	for {
+		tx, err := t.transaction(txid)
+		if err != nil {
+			return "", err
+		}
+		if int(vout) >= len(tx.Outputs) {
+			return "", fmt.Errorf("output %d not found in transaction %s", vout, txid)
+		}
+		if tx.Outputs[vout].Satoshis != 1 {
+			return "", nil
+		}
+
+		// Find the input holding the satoshi of the output
+		var offset uint64
+		for _, output := range tx.Outputs[:vout] {
+			offset += output.Satoshis
+		}
+
+		inputSats, err := t.inputSatoshis(tx)
+		if err != nil {
+			return "", err
+		}
+
+		// The satoshi came from a 1 sat input, keep following it
+		i := inputIndexAtOffset(inputSats, offset)
+		if i < 0 || inputSats[i] != 1 {
+			return fmt.Sprintf("%s_%d", txid, vout), nil
+		}
+		txid = tx.Inputs[i].SourceTXID.String()
+		vout = tx.Inputs[i].SourceTxOutIndex
+	}
+}
+
+// inputIndexAtOffset returns the index of the input holding the satoshi at offset, or -1 if there is none
+func inputIndexAtOffset(inputSats []uint64, offset uint64) int {
+	var start uint64
+	for i, sats := range inputSats {
+		if offset >= start && offset < start+sats {
+			return i
+		}
+		start += sats
+	}
+
+	return -1
+}
@@ -0,0 +1,131 @@
+package ordinals
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTraceOrdinals tests following an ordinal from its inscription through a transfer and a burn
+func TestTraceOrdinals(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+	require.NoError(t, err)
+
+	// The loader only knows serialized transactions, like a block explorer would
+	txs := make(map[string]string)
+	funding := transaction.NewTransaction()
+	funding.AddOutput(&transaction.TransactionOutput{LockingScript: placeholderScript(), Satoshis: 100000})
+	txs["0000000000000000000000000000000000000000000000000000000000000003"] = funding.Hex()
+	load := func(txid string) (*transaction.Transaction, error) {
+		rawTx, ok := txs[txid]
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction %s", txid)
+		}
+		return transaction.NewTransactionFromHex(rawTx)
+	}
+
+	// Inscribe an ordinal
+	inscribeTx, err := CreateOrdinals(&CreateOrdinalsConfig{
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: placeholderScript().String(),
+			Satoshis:     100000,
+		}},
+		Destinations: []*Destination{{
+			Address: ordAddr.AddressString,
+			Inscription: &inscription.Inscription{
+				File: inscription.File{Content: []byte("hello"), Type: "text/plain"},
+			},
+		}},
+		PaymentPk: paymentPk,
+	})
+	require.NoError(t, err)
+	inscribeTxID := inscribeTx.TxID().String()
+	txs[inscribeTxID] = inscribeTx.Hex()
+	origin := inscribeTxID + "_0"
+
+	t.Run("inscription is a new origin", func(t *testing.T) {
+		trace, err := TraceOrdinals(inscribeTx, load)
+		require.NoError(t, err)
+		assert.Empty(t, trace.Transfers)
+		assert.Equal(t, map[uint32]string{0: origin}, trace.Origins)
+	})
+
+	// Send the ordinal on
+	sendTx, err := SendOrdinals(&SendOrdinalsConfig{
+		PaymentUtxos: []*Utxo{ptr(utxoAt(inscribeTx, 1))},
+		Ordinals:     []*NftUtxo{{Utxo: utxoAt(inscribeTx, 0)}},
+		PaymentPk:    paymentPk,
+		OrdPk:        ordPk,
+		Destinations: []*Destination{{Address: ordAddr.AddressString}},
+	})
+	require.NoError(t, err)
+	txs[sendTx.TxID().String()] = sendTx.Hex()
+
+	t.Run("transfer keeps the origin", func(t *testing.T) {
+		trace, err := TraceOrdinals(sendTx, load)
+		require.NoError(t, err)
+		require.Len(t, trace.Transfers, 1)
+		assert.Equal(t, &OrdinalTransfer{
+			Input:    0,
+			Outpoint: origin,
+			Origin:   origin,
+			Output:   0,
+		}, trace.Transfers[0])
+		assert.Equal(t, map[uint32]string{0: origin}, trace.Origins)
+
+		// Loaded without the source outputs attached gives the same result
+		loaded, err := load(sendTx.TxID().String())
+		require.NoError(t, err)
+		loadedTrace, err := TraceOrdinals(loaded, load)
+		require.NoError(t, err)
+		assert.Equal(t, trace, loadedTrace)
+
+		sentOrigin, err := OrdinalOrigin(sendTx.TxID().String(), 0, load)
+		require.NoError(t, err)
+		assert.Equal(t, origin, sentOrigin)
+
+		changeOrigin, err := OrdinalOrigin(sendTx.TxID().String(), 1, load)
+		require.NoError(t, err)
+		assert.Empty(t, changeOrigin)
+	})
+
+	t.Run("burn pays the ordinal as fee", func(t *testing.T) {
+		burnTx, err := BurnOrdinals(&BurnOrdinalsConfig{
+			PaymentUtxos: []*Utxo{ptr(utxoAt(sendTx, 1))},
+			PaymentPk:    paymentPk,
+			Ordinals:     []*NftUtxo{{Utxo: utxoAt(sendTx, 0)}},
+			OrdPk:        ordPk,
+		})
+		require.NoError(t, err)
+
+		trace, err := TraceOrdinals(burnTx, load)
+		require.NoError(t, err)
+		require.Len(t, trace.Transfers, 1)
+		assert.Equal(t, origin, trace.Transfers[0].Origin)
+		assert.Equal(t, -1, trace.Transfers[0].Output)
+		assert.True(t, trace.Transfers[0].Burned)
+		assert.Empty(t, trace.Origins)
+	})
+
+	t.Run("missing source transaction", func(t *testing.T) {
+		_, err := OrdinalOrigin("0000000000000000000000000000000000000000000000000000000000000009", 0, load)
+		assert.Error(t, err)
+	})
+}
+
+// ptr returns a pointer to a copy of v
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,391 @@
+package ordinals
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	"github.com/bitcoin-sv/go-templates/template/ordlock"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// OutputKind is the kind of output detected by ParseTransaction
+type OutputKind string
+
+const (
+	// OutputKindP2PKH is a plain pay to public key hash output
+	OutputKindP2PKH OutputKind = "p2pkh"
+	// OutputKindInscription is an ord envelope with a content type and content
+	OutputKindInscription OutputKind = "inscription"
+	// OutputKindBsv21 is a BSV21 deploy+mint, transfer or burn inscription
+	OutputKindBsv21 OutputKind = "bsv21"
+	// OutputKindOrdLock is an Ordinal Lock marketplace listing
+	OutputKindOrdLock OutputKind = "ordlock"
+	// OutputKindOpReturn is an unspendable data output
+	OutputKindOpReturn OutputKind = "op-return"
+	// OutputKindUnknown is any other script
+	OutputKindUnknown OutputKind = "unknown"
+)
+
+// ParsedTransaction describes the inputs and outputs of a transaction
+type ParsedTransaction struct {
+	// TxID is the ID of the transaction
+	TxID string
+	// Inputs are the parsed inputs in order
+	Inputs []*ParsedInput
+	// Outputs are the parsed outputs in order
+	Outputs []*ParsedOutput
+}
+
+// ParsedInput describes an input of a ParsedTransaction
+type ParsedInput struct {
+	// TxID is the transaction ID of the UTXO being spent
+	TxID string
+	// Vout is the output index of the UTXO being spent
+	Vout uint32
+	// Satoshis is the value of the UTXO, nil if the input has no source output
+	Satoshis *uint64
+	// Output is the index of the output the first satoshi of the input lands in
+	// It is -1 if the satoshi is paid as fee or the satoshis of the inputs before it aren't known.
+	// Output is only where the satoshi goes, not an origin, see Origin.
+	Output int
+	// Origin is the origin outpoint of the ordinal spent by a 1 sat input
+	// It is only set by ParseTransactionWithOrigins.
+	Origin string
+}
+
+// ParsedOutput describes an output of a ParsedTransaction
+type ParsedOutput struct {
+	// Vout is the output index
+	Vout uint32
+	// Satoshis is the value of the output
+	Satoshis uint64
+	// Kind is what the output holds
+	Kind OutputKind
+	// Address is the P2PKH address that owns the output, or the seller of a listing
+	Address string
+	// ContentType is the content type of an inscription
+	ContentType string
+	// Content is the content of an inscription
+	Content []byte
+	// Map is the MAP metadata of the output, nil if there is none
+	Map map[string]string
+	// Token is the BSV21 operation of the output, also of a token listing, nil if there is none
+	Token *ParsedToken
+	// Listing is the Ordinal Lock listing of the output, nil if there is none
+	Listing *ParsedListing
+}
+
+// ParsedToken describes a BSV21 operation
+type ParsedToken struct {
+	// ID is the token ID, for a deploy+mint it is the outpoint of the output
+	ID string
+	// Op is the operation: deploy+mint, transfer or burn
+	Op string
+	// Amount is the raw token amount
+	Amount uint64
+	// Symbol is the token symbol of a deploy+mint
+	Symbol string
+	// Decimals is the number of decimal places of a deploy+mint
+	Decimals uint8
+	// Icon is the icon outpoint of a deploy+mint
+	Icon string
+}
+
+// ParsedListing describes an Ordinal Lock listing
+type ParsedListing struct {
+	// SellerAddress can cancel the listing
+	SellerAddress string
+	// Price is the number of satoshis paid to the seller on purchase
+	Price uint64
+	// PayAddress receives the payment, empty if the payout isn't P2PKH
+	PayAddress string
+}
+
+// ParseTransaction detects inscriptions, MAP metadata, BSV21 operations, listings and P2PKH outputs
+// Inputs are mapped to the output their first satoshi lands in when the source outputs are known.
+func ParseTransaction(tx *transaction.Transaction) *ParsedTransaction {
+	txid := tx.TxID().String()
+	parsed := &ParsedTransaction{TxID: txid}
+
+	// Follow the satoshis of the inputs while their values are known
+	known := true
+	for _, input := range tx.Inputs {
+		parsedInput := &ParsedInput{
+			TxID:     input.SourceTXID.String(),
+			Vout:     input.SourceTxOutIndex,
+			Satoshis: input.SourceTxSatoshis(),
+			Output:   -1,
+		}
+
+		known = known && parsedInput.Satoshis != nil
+		if known && *parsedInput.Satoshis > 0 {
+			parsedInput.Output = outputIndexAtOffset(tx, inputOffset(tx, input))
+		}
+
+		parsed.Inputs = append(parsed.Inputs, parsedInput)
+	}
+
+	for vout, output := range tx.Outputs {
+		parsed.Outputs = append(parsed.Outputs, ParseOutput(txid, uint32(vout), output))
+	}
+
+	return parsed
+}
+
+// ParseTransactionWithOrigins parses tx like ParseTransaction and sets the Origin of each 1 sat input
+// Source transactions are loaded with load to trace the ordinals back, see TraceOrdinals.
+func ParseTransactionWithOrigins(tx *transaction.Transaction, load TxLoader) (*ParsedTransaction, error) {
+	trace, err := TraceOrdinals(tx, load)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := ParseTransaction(tx)
+	for _, transfer := range trace.Transfers {
+		parsed.Inputs[transfer.Input].Origin = transfer.Origin
+		parsed.Inputs[transfer.Input].Output = transfer.Output
+	}
+
+	return parsed, nil
+}
+
+// parseToken returns the BSV21 operation of a locking script, nil if it has none
+func parseToken(txid string, vout uint32, lockingScript *script.Script) *ParsedToken {
+	token := decodeBsv21(lockingScript)
+	if token == nil {
+		return nil
+	}
+
+	parsed := &ParsedToken{
+		ID:     token.Id,
+		Op:     token.Op,
+		Amount: token.Amt,
+	}
+	if token.Op == string(bsv21.OpMint) {
+		parsed.ID = fmt.Sprintf("%s_%d", txid, vout)
+	}
+	if token.Symbol != nil {
+		parsed.Symbol = *token.Symbol
+	}
+	if token.Decimals != nil {
+		parsed.Decimals = *token.Decimals
+	}
+	if token.Icon != nil {
+		parsed.Icon = *token.Icon
+	}
+
+	return parsed
+}
+
+// decodeOrdLock decodes an Ordinal Lock listing, returning nil if the script isn't a well formed one
+// ordlock.Decode indexes the pushes between prefix and suffix without checking them, and it runs on
+// untrusted scripts, so the pushes are checked first and a panic is recovered as not a listing.
+func decodeOrdLock(lockingScript *script.Script) (listing *ordlock.OrdLock) {
+	start := bytes.Index(*lockingScript, ordlock.OrdLockPrefix)
+	if start < 0 {
+		return nil
+	}
+	start += len(ordlock.OrdLockPrefix)
+
+	end := bytes.Index((*lockingScript)[start:], ordlock.OrdLockSuffix)
+	if end < 0 {
+		return nil
+	}
+
+	// The seller key hash and the payout output
+	ops, err := script.DecodeScript((*lockingScript)[start : start+end])
+	if err != nil || len(ops) < 2 || len(ops[0].Data) != 20 {
+		return nil
+	}
+
+	defer func() {
+		if recover() != nil {
+			listing = nil
+		}
+	}()
+
+	return ordlock.Decode(lockingScript)
+}
+
+// ParseOutput detects what an output of the transaction txid holds
+func ParseOutput(txid string, vout uint32, output *transaction.TransactionOutput) *ParsedOutput {
+	parsed := &ParsedOutput{
+		Vout:     vout,
+		Satoshis: output.Satoshis,
+		Kind:     OutputKindUnknown,
+	}
+
+	lockingScript := output.LockingScript
+	if lockingScript == nil {
+		return parsed
+	}
+
+	parsed.Map = decodeMap(*lockingScript)
+
+	// Listings have no envelope, check them first
+	if listing := decodeOrdLock(lockingScript); listing != nil {
+		parsed.Kind = OutputKindOrdLock
+		parsed.Listing = &ParsedListing{
+			SellerAddress: listing.Seller.AddressString,
+			Price:         listing.Price,
+		}
+		parsed.Address = listing.Seller.AddressString
+
+		// The payout is a serialized output, it is paid to an address if it is P2PKH
+		payOutput := &transaction.TransactionOutput{}
+		if _, err := payOutput.ReadFrom(bytes.NewReader(listing.PayOut)); err == nil {
+			parsed.Listing.PayAddress = p2pkhAddress(*payOutput.LockingScript)
+		}
+
+		// Token listings carry their tokens in a transfer inscription around the lock
+		parsed.Token = parseToken(txid, vout, lockingScript)
+		return parsed
+	}
+
+	if insc := inscription.Decode(lockingScript); insc != nil {
+		parsed.Kind = OutputKindInscription
+		parsed.ContentType = insc.File.Type
+		parsed.Content = insc.File.Content
+
+		// The owner is the P2PKH before or after the envelope
+		parsed.Address = p2pkhAddress(insc.ScriptSuffix)
+		if parsed.Address == "" {
+			parsed.Address = p2pkhAddress(insc.ScriptPrefix)
+		}
+
+		if parsed.Token = parseToken(txid, vout, lockingScript); parsed.Token != nil {
+			parsed.Kind = OutputKindBsv21
+		}
+		return parsed
+	}
+
+	switch {
+	case lockingScript.IsP2PKH():
+		parsed.Kind = OutputKindP2PKH
+		parsed.Address = p2pkhAddress(*lockingScript)
+	case lockingScript.IsData():
+		parsed.Kind = OutputKindOpReturn
+	}
+
+	return parsed
+}
+
+// decodeBsv21 reads the BSV21 inscription of a locking script
+// bsv21.Decode only accepts string amounts with a "p" field, which bsv21.Lock doesn't write,
+// so numbers and strings are both accepted here.
+func decodeBsv21(lockingScript *script.Script) *bsv21.Bsv21 {
+	insc := inscription.Decode(lockingScript)
+	if insc == nil || insc.File.Type != "application/bsv-20" {
+		return nil
+	}
+
+	var data struct {
+		P    string          `json:"p"`
+		ID   string          `json:"id"`
+		Op   string          `json:"op"`
+		Amt  json.RawMessage `json:"amt"`
+		Sym  *string         `json:"sym"`
+		Dec  json.RawMessage `json:"dec"`
+		Icon *string         `json:"icon"`
+	}
+	if err := json.Unmarshal(insc.File.Content, &data); err != nil {
+		return nil
+	}
+	if data.P != "" && data.P != "bsv-20" {
+		return nil
+	}
+
+	token := &bsv21.Bsv21{
+		Id:     data.ID,
+		Op:     strings.ToLower(data.Op),
+		Symbol: data.Sym,
+		Icon:   data.Icon,
+		Insc:   insc,
+	}
+
+	// Amounts may be quoted or not
+	amt, ok := jsonUint(data.Amt, 64)
+	if !ok {
+		return nil
+	}
+	token.Amt = amt
+
+	if len(data.Dec) > 0 {
+		dec, ok := jsonUint(data.Dec, 8)
+		if !ok || dec > 18 {
+			return nil
+		}
+		decimals := uint8(dec)
+		token.Decimals = &decimals
+	}
+
+	return token
+}
+
+// jsonUint parses a JSON number or a quoted number
+func jsonUint(raw json.RawMessage, bitSize int) (uint64, bool) {
+	value, err := strconv.ParseUint(strings.Trim(string(raw), `"`), 10, bitSize)
+	return value, err == nil
+}
+
+// decodeMap reads the MAP SET key value pairs after the OP_RETURN of a script
+// Other protocols separated by "|" are skipped. It returns nil if there is no MAP data.
+func decodeMap(lockingScript script.Script) map[string]string {
+	// Collect the pushes after OP_RETURN
+	var data [][]byte
+	afterReturn := false
+	for pos := 0; pos < len(lockingScript); {
+		op, err := lockingScript.ReadOp(&pos)
+		if err != nil {
+			break
+		}
+		if op.Op == script.OpRETURN {
+			afterReturn = true
+			continue
+		}
+		if afterReturn {
+			data = append(data, op.Data)
+		}
+	}
+
+	var metadata map[string]string
+	for i := 0; i < len(data); i++ {
+		if string(data[i]) != MAP_PREFIX || i+1 >= len(data) || string(data[i+1]) != "SET" {
+			continue
+		}
+
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		for i += 2; i+1 < len(data) && string(data[i]) != "|"; i += 2 {
+			metadata[string(data[i])] = string(data[i+1])
+		}
+	}
+
+	return metadata
+}
+
+// p2pkhAddress returns the address of a script starting with a P2PKH, or "" if it doesn't
+func p2pkhAddress(lockingScript []byte) string {
+	if len(lockingScript) < 25 {
+		return ""
+	}
+
+	p2pkhScript := script.Script(lockingScript[:25])
+	if !p2pkhScript.IsP2PKH() {
+		return ""
+	}
+
+	addr, err := script.NewAddressFromPublicKeyHash(p2pkhScript[3:23], true)
+	if err != nil {
+		return ""
+	}
+
+	return addr.AddressString
+}
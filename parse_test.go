@@ -0,0 +1,228 @@
+package ordinals
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	"github.com/bitcoin-sv/go-templates/template/ordlock"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseTransaction tests reading ordinal outputs back from transactions
+func TestParseTransaction(t *testing.T) {
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// Parsed addresses are derived from the script, so use addresses with a valid checksum
+	ordAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+	require.NoError(t, err)
+	paymentAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	require.NoError(t, err)
+	recipient := ordAddr.AddressString
+	changeAddress := paymentAddr.AddressString
+
+	paymentUtxos := []*Utxo{{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}}
+
+	t.Run("inscription, transfer, MAP and change", func(t *testing.T) {
+		b := NewBuilder(BuildOptions{})
+		require.NoError(t, b.AddOrdinal(&NftUtxo{Utxo: Utxo{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+			Vout:         1,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     1,
+		}}, ordPk, &Destination{
+			Address: recipient,
+			Inscription: &inscription.Inscription{
+				File: inscription.File{Content: []byte("hello"), Type: "text/plain"},
+			},
+		}))
+		_, _, err := b.AddTokenTransfer(&TransferBsv21TokenConfig{
+			Protocol: TokenTypeBSV21,
+			TokenID:  tokenID,
+			InputTokens: []*TokenUtxo{{
+				Utxo: Utxo{
+					TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+					Vout:         0,
+					ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+					Satoshis:     1,
+				},
+				TokenID:  tokenID,
+				Protocol: TokenTypeBSV21,
+				Amount:   1000,
+			}},
+			Distributions: []*TokenDistribution{{Address: changeAddress, Tokens: 400}},
+			OrdPk:         ordPk,
+		})
+		require.NoError(t, err)
+		require.NoError(t, b.AddPaymentUtxos(paymentUtxos, paymentPk))
+		require.NoError(t, b.AddOpReturn(mapOpReturnData(map[string][]byte{"app": []byte("test"), "type": []byte("ord")})...))
+		require.NoError(t, b.Change(changeAddress, nil))
+		require.NoError(t, b.Fee(nil))
+		tx, err := b.Sign()
+		require.NoError(t, err)
+
+		parsed := ParseTransaction(tx)
+		assert.Equal(t, tx.TxID().String(), parsed.TxID)
+		require.Len(t, parsed.Outputs, 5)
+
+		inscribed := parsed.Outputs[0]
+		assert.Equal(t, OutputKindInscription, inscribed.Kind)
+		assert.Equal(t, "text/plain", inscribed.ContentType)
+		assert.Equal(t, []byte("hello"), inscribed.Content)
+		assert.Equal(t, recipient, inscribed.Address)
+
+		transfer := parsed.Outputs[1]
+		assert.Equal(t, OutputKindBsv21, transfer.Kind)
+		assert.Equal(t, &ParsedToken{ID: tokenID, Op: "transfer", Amount: 400}, transfer.Token)
+		assert.Equal(t, changeAddress, transfer.Address)
+		assert.Equal(t, uint64(600), parsed.Outputs[2].Token.Amount)
+
+		opReturn := parsed.Outputs[3]
+		assert.Equal(t, OutputKindOpReturn, opReturn.Kind)
+		assert.Equal(t, map[string]string{"app": "test", "type": "ord"}, opReturn.Map)
+
+		change := parsed.Outputs[4]
+		assert.Equal(t, OutputKindP2PKH, change.Kind)
+		assert.Equal(t, changeAddress, change.Address)
+		assert.Nil(t, change.Map)
+
+		// Each input's first satoshi lands in the output at the same position, payment in change
+		require.Len(t, parsed.Inputs, 3)
+		assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000004", parsed.Inputs[0].TxID)
+		assert.Equal(t, uint32(1), parsed.Inputs[0].Vout)
+		assert.Equal(t, 0, parsed.Inputs[0].Output)
+		assert.Equal(t, 1, parsed.Inputs[1].Output)
+		assert.Equal(t, 2, parsed.Inputs[2].Output)
+	})
+
+	t.Run("deploy", func(t *testing.T) {
+		tx, err := DeployBsv21Token(&DeployBsv21TokenConfig{
+			Symbol:              "TEST",
+			Icon:                "icon_0",
+			Utxos:               paymentUtxos,
+			InitialDistribution: &TokenDistribution{Address: recipient, Tokens: 21000000},
+			PaymentPk:           paymentPk,
+			DestinationAddress:  recipient,
+		})
+		require.NoError(t, err)
+
+		parsed := ParseTransaction(tx)
+		token := parsed.Outputs[0].Token
+		require.NotNil(t, token)
+		assert.Equal(t, fmt.Sprintf("%s_0", parsed.TxID), token.ID)
+		assert.Equal(t, "deploy+mint", token.Op)
+		assert.Equal(t, uint64(21000000), token.Amount)
+		assert.Equal(t, "TEST", token.Symbol)
+		assert.Equal(t, "icon_0", token.Icon)
+	})
+
+	t.Run("ord lock and unknown inputs", func(t *testing.T) {
+		seller := ordAddr
+		payScript, err := p2pkh.Lock(seller)
+		require.NoError(t, err)
+		payOut := (&transaction.TransactionOutput{LockingScript: payScript, Satoshis: 5000}).Bytes()
+
+		// Build the listing script: prefix, seller hash, payout, suffix
+		lockingScript := script.NewFromBytes(append([]byte{}, ordlock.OrdLockPrefix...))
+		require.NoError(t, lockingScript.AppendPushData(seller.PublicKeyHash))
+		require.NoError(t, lockingScript.AppendPushData(payOut))
+		*lockingScript = append(*lockingScript, ordlock.OrdLockSuffix...)
+
+		tx := transaction.NewTransaction()
+		require.NoError(t, tx.AddInputFrom(
+			"0000000000000000000000000000000000000000000000000000000000000004", 0,
+			"76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac", 1, nil,
+		))
+		tx.AddInput(&transaction.TransactionInput{
+			SourceTXID:       tx.Inputs[0].SourceTXID,
+			SourceTxOutIndex: 1,
+		})
+		tx.AddOutput(&transaction.TransactionOutput{LockingScript: lockingScript, Satoshis: 1})
+
+		parsed := ParseTransaction(tx)
+		listing := parsed.Outputs[0]
+		assert.Equal(t, OutputKindOrdLock, listing.Kind)
+		assert.Equal(t, &ParsedListing{
+			SellerAddress: recipient,
+			Price:         5000,
+			PayAddress:    recipient,
+		}, listing.Listing)
+
+		assert.Equal(t, 0, parsed.Inputs[0].Output)
+		assert.Nil(t, parsed.Inputs[1].Satoshis)
+		assert.Equal(t, -1, parsed.Inputs[1].Output)
+	})
+
+	t.Run("malformed ord lock", func(t *testing.T) {
+		// Prefix, a single push and suffix, ordlock.Decode alone panics on it
+		lockingScript := script.NewFromBytes(append([]byte{}, ordlock.OrdLockPrefix...))
+		require.NoError(t, lockingScript.AppendPushData(ordAddr.PublicKeyHash))
+		*lockingScript = append(*lockingScript, ordlock.OrdLockSuffix...)
+
+		tx := transaction.NewTransaction()
+		tx.AddOutput(&transaction.TransactionOutput{LockingScript: lockingScript, Satoshis: 1})
+
+		parsed := ParseTransaction(tx)
+		assert.Equal(t, OutputKindUnknown, parsed.Outputs[0].Kind)
+		assert.Nil(t, parsed.Outputs[0].Listing)
+	})
+}
+
+// TestParseTransactionWithOrigins tests setting the origin of 1 sat inputs
+func TestParseTransactionWithOrigins(t *testing.T) {
+	lockingScript := placeholderScript()
+
+	// funding pays for an inscription, which is sent on by transfer
+	funding := transaction.NewTransaction()
+	funding.AddOutput(&transaction.TransactionOutput{LockingScript: lockingScript, Satoshis: 100000})
+
+	inscribe := transaction.NewTransaction()
+	require.NoError(t, inscribe.AddInputFrom(funding.TxID().String(), 0, lockingScript.String(), 100000, nil))
+	inscribe.AddOutput(&transaction.TransactionOutput{LockingScript: lockingScript, Satoshis: 1})
+	inscribe.AddOutput(&transaction.TransactionOutput{LockingScript: lockingScript, Satoshis: 99000})
+
+	// The 1 sat input isn't first, its source value is only known from the loader
+	transfer := transaction.NewTransaction()
+	transfer.AddInput(&transaction.TransactionInput{SourceTXID: inscribe.TxID(), SourceTxOutIndex: 1})
+	transfer.AddInput(&transaction.TransactionInput{SourceTXID: inscribe.TxID(), SourceTxOutIndex: 0})
+	transfer.AddOutput(&transaction.TransactionOutput{LockingScript: lockingScript, Satoshis: 99000})
+	transfer.AddOutput(&transaction.TransactionOutput{LockingScript: lockingScript, Satoshis: 1})
+
+	txs := map[string]*transaction.Transaction{
+		funding.TxID().String():  funding,
+		inscribe.TxID().String(): inscribe,
+	}
+	load := func(txid string) (*transaction.Transaction, error) {
+		tx, ok := txs[txid]
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction %s", txid)
+		}
+		return tx, nil
+	}
+
+	parsed, err := ParseTransactionWithOrigins(transfer, load)
+	require.NoError(t, err)
+	assert.Equal(t, "", parsed.Inputs[0].Origin)
+	assert.Equal(t, inscribe.TxID().String()+"_0", parsed.Inputs[1].Origin)
+	assert.Equal(t, 1, parsed.Inputs[1].Output)
+
+	_, err = ParseTransactionWithOrigins(transfer, func(txid string) (*transaction.Transaction, error) {
+		return nil, fmt.Errorf("unknown transaction %s", txid)
+	})
+	assert.Error(t, err)
+}
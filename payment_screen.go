@@ -0,0 +1,76 @@
+package ordinals
+
+import (
+	"fmt"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	"github.com/bsv-blockchain/go-sdk/script"
+)
+
+// PaymentScreen decides which UTXOs are safe to spend as payment satoshis
+// The zero value excludes 1 sat UTXOs and UTXOs whose script carries an ordinal, set the fields to opt in.
+type PaymentScreen struct {
+	// AllowOneSat accepts 1 sat UTXOs, which usually hold an ordinal even when the script is a plain P2PKH
+	AllowOneSat bool
+	// AllowOrdinals accepts UTXOs whose script carries an inscription, a BSV21 payload or an ordinal lock
+	AllowOrdinals bool
+}
+
+// ExcludedUtxo is a UTXO removed by ScreenPaymentUtxos
+type ExcludedUtxo struct {
+	// Utxo is the excluded UTXO
+	Utxo *Utxo
+	// Reason describes why it isn't safe to spend as payment
+	Reason string
+}
+
+// ScreenPaymentUtxos splits UTXOs into the ones safe to spend as payment and the ones that may hold an ordinal
+// Use it on UTXOs from an address that also holds ordinals before passing them to a builder.
+func ScreenPaymentUtxos(utxos []*Utxo, screen PaymentScreen) (safe []*Utxo, excluded []*ExcludedUtxo) {
+	for _, utxo := range utxos {
+		if reason := screen.check(utxo); reason != "" {
+			excluded = append(excluded, &ExcludedUtxo{Utxo: utxo, Reason: reason})
+			continue
+		}
+		safe = append(safe, utxo)
+	}
+
+	return safe, excluded
+}
+
+// check returns why a UTXO isn't safe to spend as payment, or "" if it is
+func (s PaymentScreen) check(utxo *Utxo) string {
+	if !s.AllowOrdinals {
+		if payload := ordinalPayload(utxo.ScriptPubKey); payload != "" {
+			return fmt.Sprintf("holds %s", payload)
+		}
+	}
+
+	if !s.AllowOneSat && utxo.Satoshis == 1 {
+		return "holds 1 satoshi and may be an ordinal"
+	}
+
+	return ""
+}
+
+// ordinalPayload describes the ordinal payload of a locking script in hex, or returns "" if there is none
+// Scripts that don't parse are left to AddInputFrom to report.
+func ordinalPayload(scriptHex string) string {
+	lockingScript, err := script.NewFromHex(scriptHex)
+	if err != nil {
+		return ""
+	}
+
+	// Tokens are inscriptions too, so check for them first
+	if token := decodeBsv21(lockingScript); token != nil {
+		return fmt.Sprintf("token %s", token.Id)
+	}
+	if inscription.Decode(lockingScript) != nil {
+		return "an inscription"
+	}
+	if decodeOrdLock(lockingScript) != nil {
+		return "an ordinal lock listing"
+	}
+
+	return ""
+}
@@ -0,0 +1,102 @@
+package ordinals
+
+import (
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	"github.com/bitcoin-sv/go-templates/template/ordlock"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScreenPaymentUtxos tests keeping ordinals out of the payment UTXOs
+func TestScreenPaymentUtxos(t *testing.T) {
+	p2pkhScript, err := script.NewFromHex("76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac")
+	require.NoError(t, err)
+	inscribed, err := (&inscription.Inscription{
+		File:         inscription.File{Content: []byte("hello"), Type: "text/plain"},
+		ScriptSuffix: *p2pkhScript,
+	}).Lock()
+	require.NoError(t, err)
+
+	plain := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: p2pkhScript.String(),
+		Satoshis:     100000,
+	}
+	oneSat := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         1,
+		ScriptPubKey: p2pkhScript.String(),
+		Satoshis:     1,
+	}
+	ordinal := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         2,
+		ScriptPubKey: inscribed.String(),
+		Satoshis:     1,
+	}
+	utxos := []*Utxo{plain, oneSat, ordinal}
+
+	t.Run("excluded by default", func(t *testing.T) {
+		safe, excluded := ScreenPaymentUtxos(utxos, PaymentScreen{})
+		assert.Equal(t, []*Utxo{plain}, safe)
+		require.Len(t, excluded, 2)
+		assert.Same(t, oneSat, excluded[0].Utxo)
+		assert.Contains(t, excluded[0].Reason, "1 satoshi")
+		assert.Same(t, ordinal, excluded[1].Utxo)
+		assert.Contains(t, excluded[1].Reason, "inscription")
+	})
+
+	t.Run("opt in", func(t *testing.T) {
+		safe, excluded := ScreenPaymentUtxos(utxos, PaymentScreen{AllowOneSat: true})
+		assert.Equal(t, []*Utxo{plain, oneSat}, safe)
+		assert.Len(t, excluded, 1)
+
+		safe, excluded = ScreenPaymentUtxos(utxos, PaymentScreen{AllowOneSat: true, AllowOrdinals: true})
+		assert.Equal(t, utxos, safe)
+		assert.Empty(t, excluded)
+	})
+
+	t.Run("builders reject 1 sat payments", func(t *testing.T) {
+		paymentPk, err := ec.NewPrivateKey()
+		require.NoError(t, err)
+
+		config := &SendUtxosConfig{
+			Utxos:     []*Utxo{plain, oneSat},
+			PaymentPk: paymentPk,
+			Payments:  []*PayToAddress{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Satoshis: 1000}},
+		}
+
+		_, err = SendUtxos(config)
+		assert.ErrorIs(t, err, ErrOrdinalPayment)
+		assert.ErrorIs(t, Validate(config), ErrOrdinalPayment)
+
+		config.PaymentScreen = PaymentScreen{AllowOneSat: true}
+		tx, err := SendUtxos(config)
+		require.NoError(t, err)
+		assert.Len(t, tx.Inputs, 2)
+		assert.NoError(t, Validate(config))
+	})
+
+	t.Run("malformed ord lock", func(t *testing.T) {
+		// Prefix, a single push and suffix, ordlock.Decode alone panics on it
+		lockingScript := script.NewFromBytes(append([]byte{}, ordlock.OrdLockPrefix...))
+		require.NoError(t, lockingScript.AppendPushData(make([]byte, 20)))
+		*lockingScript = append(*lockingScript, ordlock.OrdLockSuffix...)
+
+		malformed := &Utxo{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         3,
+			ScriptPubKey: lockingScript.String(),
+			Satoshis:     1000,
+		}
+
+		safe, excluded := ScreenPaymentUtxos([]*Utxo{malformed}, PaymentScreen{})
+		assert.Equal(t, []*Utxo{malformed}, safe)
+		assert.Empty(t, excluded)
+	})
+}
@@ -1,95 +1,61 @@
 package ordinals
 
 import (
+	"errors"
 	"fmt"
 
-	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/bsv-blockchain/go-sdk/transaction"
-	fee_model "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
-	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
 )
 
 // SendUtxos sends utxos to the given destinations
 func SendUtxos(config *SendUtxosConfig) (*transaction.Transaction, error) {
-	// Create a new transaction
-	tx := transaction.NewTransaction()
-
-	// Add inputs
-	for _, utxo := range config.Utxos {
-		unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create unlocker: %w", err)
-		}
-
-		err = tx.AddInputFrom(
-			utxo.TxID,
-			utxo.Vout,
-			utxo.ScriptPubKey,
-			utxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add input: %w", err)
-		}
+	b := newTxBuilder(false)
+	if err := buildSendUtxos(b, config); err != nil {
+		return nil, err
 	}
 
-	// Add payment outputs
-	for _, payment := range config.Payments {
-		dstAddr, err := script.NewAddressFromString(payment.Address)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create destination address: %w", err)
-		}
+	return b.tx, nil
+}
 
-		lockingScript, err := p2pkh.Lock(dstAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
-		}
+// buildSendUtxos adds the inputs and outputs of SendUtxos to the builder
+func buildSendUtxos(b *Builder, config *SendUtxosConfig) error {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
 
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: lockingScript,
-			Satoshis:      payment.Satoshis,
-		})
+	// Add inputs
+	err := b.AddPaymentUtxos(config.Utxos, config.PaymentPk)
+	if err != nil {
+		return err
 	}
 
-	// Add change output if needed
-	if config.ChangeAddress != "" {
-		changeAddr, err := script.NewAddressFromString(config.ChangeAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change address: %w", err)
-		}
-
-		changeScript, err := p2pkh.Lock(changeAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change script: %w", err)
-		}
-
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: changeScript,
-			Change:        true,
-		})
+	// Add payment outputs
+	err = b.addPayments(config.Payments)
+	if err != nil {
+		return err
 	}
 
-	// Set fee rate using SatsPerKb if provided, otherwise use the default value
-	feeRate := config.SatsPerKb
-	if feeRate == 0 {
-		feeRate = DEFAULT_SAT_PER_KB
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return fmt.Errorf("failed to add change output: %w", err)
 	}
 
-	// Create fee model for computation
-	feeModel := &fee_model.SatoshisPerKilobyte{
-		Satoshis: feeRate,
-	}
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
 
-	err := tx.Fee(feeModel, transaction.ChangeDistributionEqual)
+	err = b.Fee(feeModel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate fee: %w", err)
+		if errors.Is(err, ErrInsufficientFunds) {
+			return fmt.Errorf("not enough funds to send utxos: %w", err)
+		}
+		return fmt.Errorf("failed to calculate fee: %w", err)
 	}
 
 	// Sign the transaction
-	err = tx.Sign()
+	_, err = b.Sign()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	return tx, nil
+	return nil
 }
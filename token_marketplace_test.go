@@ -3,9 +3,12 @@ package ordinals
 import (
 	"testing"
 
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
+	"github.com/bitcoin-sv/go-templates/template/ordlock"
 	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateOrdTokenListings(t *testing.T) {
@@ -74,19 +77,36 @@ func TestCreateOrdTokenListings(t *testing.T) {
 	assert.Equal(t, 2, len(tx.Inputs))                 // 1 payment input + 1 token input
 	assert.GreaterOrEqual(t, len(tx.Outputs), 2)       // At least 1 token output + change
 	assert.Equal(t, uint64(1), tx.Outputs[0].Satoshis) // 1 sat for ordinals
+
+	// The listing is an Ordinal Lock carrying the tokens in a transfer inscription
+	token := decodeBsv21(tx.Outputs[0].LockingScript)
+	require.NotNil(t, token)
+	assert.Equal(t, string(bsv21.OpTransfer), token.Op)
+	assert.Equal(t, tokenUtxo.TokenID, token.Id)
+	assert.Equal(t, tokenUtxo.Amount, token.Amt)
+
+	listing := ordlock.Decode(tx.Outputs[0].LockingScript)
+	require.NotNil(t, listing)
+	assert.Equal(t, ordAddr.PublicKeyHash, listing.Seller.PublicKeyHash)
+	assert.Equal(t, uint64(10000), listing.Price)
+
+	// Parsing keeps both the listing and its tokens
+	parsed := ParseTransaction(tx).Outputs[0]
+	assert.Equal(t, OutputKindOrdLock, parsed.Kind)
+	require.NotNil(t, parsed.Listing)
+	assert.Equal(t, uint64(10000), parsed.Listing.Price)
+	assert.Equal(t, &ParsedToken{ID: tokenUtxo.TokenID, Op: "transfer", Amount: 1000}, parsed.Token)
 }
 
-func TestPurchaseOrdTokenListing(t *testing.T) {
-	// Create private keys for testing
-	paymentPk, err := ec.NewPrivateKey()
-	assert.NoError(t, err)
+// listTokens lists 1000 tokens for price satoshis and returns the listing UTXO
+// ordPk can cancel the listing and payAddress receives the price.
+func listTokens(t *testing.T, ordPk *ec.PrivateKey, payAddress string, price uint64) *TokenUtxo {
+	t.Helper()
 
-	// Get address
-	paymentAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
-	assert.NoError(t, err)
+	ordAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+	require.NoError(t, err)
 
-	// Mock a token listing UTXO
-	listingUtxo := &TokenUtxo{
+	tokenUtxo := &TokenUtxo{
 		Utxo: Utxo{
 			TxID:         "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
 			Vout:         0,
@@ -96,9 +116,58 @@ func TestPurchaseOrdTokenListing(t *testing.T) {
 		TokenID:  "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890_0",
 		Protocol: TokenTypeBSV21,
 		Amount:   1000,
-		Decimals: 0,
 	}
 
+	tx, err := CreateOrdTokenListings(&CreateOrdTokenListingsConfig{
+		Utxos: []*Utxo{{
+			TxID:         "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567891",
+			Vout:         1,
+			ScriptPubKey: "76a914a5f427350ffc9a9f0c02e823ff5c3d77c9846fec88ac",
+			Satoshis:     100000,
+		}},
+		Listings: []*struct {
+			PayAddress  string
+			Price       uint64
+			ListingUtxo *TokenUtxo
+			OrdAddress  string
+		}{{
+			PayAddress:  payAddress,
+			Price:       price,
+			ListingUtxo: tokenUtxo,
+			OrdAddress:  ordAddr.AddressString,
+		}},
+		PaymentPk: ordPk,
+		OrdPk:     ordPk,
+	})
+	require.NoError(t, err)
+
+	return &TokenUtxo{
+		Utxo: Utxo{
+			TxID:         tx.TxID().String(),
+			Vout:         0,
+			ScriptPubKey: tx.Outputs[0].LockingScript.String(),
+			Satoshis:     1,
+		},
+		TokenID:  tokenUtxo.TokenID,
+		Protocol: TokenTypeBSV21,
+		Amount:   tokenUtxo.Amount,
+	}
+}
+
+func TestPurchaseOrdTokenListing(t *testing.T) {
+	// Create private keys for testing
+	paymentPk, err := ec.NewPrivateKey()
+	assert.NoError(t, err)
+
+	// Get address
+	paymentAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	assert.NoError(t, err)
+
+	// List the tokens, the seller is paid 10000 satoshis
+	sellerPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	listingUtxo := listTokens(t, sellerPk, "1BitcoinEaterAddressDontSendf59kuE", 10000)
+
 	// Mock a payment UTXO
 	paymentUtxo := &Utxo{
 		TxID:         "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567891",
@@ -131,9 +200,17 @@ func TestPurchaseOrdTokenListing(t *testing.T) {
 	assert.NotNil(t, tx)
 
 	// Verify the transaction structure
-	assert.Equal(t, 2, len(tx.Inputs))                 // 1 listing input + 1 payment input
-	assert.GreaterOrEqual(t, len(tx.Outputs), 3)       // token output + payment output + additional payment + change
-	assert.Equal(t, uint64(1), tx.Outputs[0].Satoshis) // 1 sat for ordinals
+	assert.Equal(t, 2, len(tx.Inputs))                     // 1 listing input + 1 payment input
+	assert.Equal(t, 4, len(tx.Outputs))                    // token output + payout + additional payment + change
+	assert.Equal(t, uint64(1), tx.Outputs[0].Satoshis)     // 1 sat for ordinals
+	assert.Equal(t, uint64(10000), tx.Outputs[1].Satoshis) // the price decoded from the listing
+
+	token := decodeBsv21(tx.Outputs[0].LockingScript)
+	require.NotNil(t, token)
+	assert.Equal(t, uint64(1000), token.Amt)
+
+	// The listing script accepts the purchase
+	verifyInput(t, tx, 0)
 }
 
 func TestCancelOrdTokenListings(t *testing.T) {
@@ -147,19 +224,8 @@ func TestCancelOrdTokenListings(t *testing.T) {
 	paymentAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
 	assert.NoError(t, err)
 
-	// Mock a token listing UTXO
-	listingUtxo := &TokenUtxo{
-		Utxo: Utxo{
-			TxID:         "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
-			Vout:         0,
-			ScriptPubKey: "76a914b10d25c5ba3dda4e217524c7f7a6d6c53d2ae85588ac",
-			Satoshis:     1,
-		},
-		TokenID:  "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890_0",
-		Protocol: TokenTypeBSV21,
-		Amount:   1000,
-		Decimals: 0,
-	}
+	// List the tokens, ordPk can cancel the listing
+	listingUtxo := listTokens(t, ordPk, paymentAddr.AddressString, 10000)
 
 	// Mock a payment UTXO
 	paymentUtxo := &Utxo{
@@ -188,4 +254,7 @@ func TestCancelOrdTokenListings(t *testing.T) {
 	assert.Equal(t, 2, len(tx.Inputs))                 // 1 listing input + 1 payment input
 	assert.GreaterOrEqual(t, len(tx.Outputs), 2)       // token output + change
 	assert.Equal(t, uint64(1), tx.Outputs[0].Satoshis) // 1 sat for ordinals
+
+	// The listing script accepts the seller's signature
+	verifyInput(t, tx, 0)
 }
@@ -0,0 +1,219 @@
+package ordinals
+
+import (
+	"fmt"
+	"math"
+)
+
+// TokenSplitStrategy decides how token change is divided across outputs
+// Amounts are raw token amounts (without decimals). Implementations must be
+// deterministic and return amounts that are all non-zero and add up to total.
+type TokenSplitStrategy interface {
+	// Split divides total into output amounts
+	// outputs is the requested number of outputs and threshold the minimum amount per output (0 for none)
+	Split(total uint64, outputs int, threshold uint64) ([]uint64, error)
+}
+
+// EvenSplit divides tokens evenly across outputs, the last output receives any remainder
+// This is the default strategy when TokenSplitConfig.Strategy is nil
+type EvenSplit struct{}
+
+// Split implements TokenSplitStrategy
+func (EvenSplit) Split(total uint64, outputs int, threshold uint64) ([]uint64, error) {
+	if total == 0 {
+		return nil, nil
+	}
+
+	outputs = clampSplitOutputs(total, outputs)
+
+	// If tokens per split is below threshold, reduce the number of outputs
+	// This ensures each output has at least the threshold amount of tokens
+	if threshold > 0 && total/uint64(outputs) < threshold {
+		outputs = int(total / threshold)
+		if outputs == 0 {
+			outputs = 1 // Ensure at least one output
+		}
+	}
+
+	// Last output gets any remainder
+	perOutput := total / uint64(outputs)
+	amounts := make([]uint64, outputs)
+	for i := range amounts {
+		amounts[i] = perOutput
+	}
+	amounts[outputs-1] += total - perOutput*uint64(outputs)
+
+	return amounts, nil
+}
+
+// GeometricSplit divides tokens into a ladder where each output is Ratio times the previous one
+// A Ratio of 2 splits 700 tokens over 3 outputs as 100, 200, 400. Rounding leftovers go to the last output.
+type GeometricSplit struct {
+	// Ratio is the size of each output relative to the one before it, must be positive
+	Ratio float64
+}
+
+// Split implements TokenSplitStrategy
+func (s GeometricSplit) Split(total uint64, outputs int, threshold uint64) ([]uint64, error) {
+	if s.Ratio <= 0 || math.IsNaN(s.Ratio) || math.IsInf(s.Ratio, 0) {
+		return nil, fmt.Errorf("geometric split ratio must be a positive number, got %v", s.Ratio)
+	}
+
+	if total == 0 {
+		return nil, nil
+	}
+
+	// Drop outputs until every rung of the ladder is non-zero and meets the threshold
+	for outputs = clampSplitOutputs(total, outputs); outputs > 1; outputs-- {
+		amounts := geometricAmounts(total, outputs, s.Ratio)
+		if amounts != nil && minAmount(amounts) >= threshold {
+			return amounts, nil
+		}
+	}
+
+	return []uint64{total}, nil
+}
+
+// geometricAmounts returns the ladder amounts for a fixed number of outputs, or nil if any rung is empty
+func geometricAmounts(total uint64, outputs int, ratio float64) []uint64 {
+	// Calculate the weight of each rung
+	weights := make([]float64, outputs)
+	var sum float64
+	for i := range weights {
+		weights[i] = math.Pow(ratio, float64(i))
+		sum += weights[i]
+	}
+
+	amounts := make([]uint64, outputs)
+	tokensLeft := total
+	for i := range amounts {
+		// Last output gets any remainder
+		if i == outputs-1 {
+			amounts[i] = tokensLeft
+			break
+		}
+
+		amount := uint64(math.Floor(float64(total) * weights[i] / sum))
+		if amount > tokensLeft {
+			amount = tokensLeft
+		}
+		amounts[i] = amount
+		tokensLeft -= amount
+	}
+
+	for _, amount := range amounts {
+		if amount == 0 {
+			return nil
+		}
+	}
+
+	return amounts
+}
+
+// ExplicitSplit creates one output per listed amount
+// Any tokens not covered by Amounts are added as one extra output. The requested
+// number of outputs and the threshold are ignored.
+type ExplicitSplit struct {
+	// Amounts are the raw token amounts of each output
+	Amounts []uint64
+}
+
+// Split implements TokenSplitStrategy
+func (s ExplicitSplit) Split(total uint64, _ int, _ uint64) ([]uint64, error) {
+	if len(s.Amounts) == 0 {
+		return nil, fmt.Errorf("explicit split requires at least one amount")
+	}
+
+	amounts := make([]uint64, 0, len(s.Amounts)+1)
+	var sum uint64
+	for i, amount := range s.Amounts {
+		if amount == 0 {
+			return nil, fmt.Errorf("explicit split amount %d must be greater than zero", i)
+		}
+		if amount > total-sum {
+			return nil, fmt.Errorf("explicit split amounts exceed token change of %d", total)
+		}
+		sum += amount
+		amounts = append(amounts, amount)
+	}
+
+	// Return whatever is left as a final output
+	if sum < total {
+		amounts = append(amounts, total-sum)
+	}
+
+	return amounts, nil
+}
+
+// RoundLotSplit divides tokens into outputs that are whole multiples of LotSize
+// Lots are spread as evenly as possible over the outputs, earlier outputs taking
+// any extra lot. Tokens that don't fill a lot are added as a final odd-lot output.
+type RoundLotSplit struct {
+	// LotSize is the raw token amount of one lot, must be greater than zero
+	LotSize uint64
+}
+
+// Split implements TokenSplitStrategy
+func (s RoundLotSplit) Split(total uint64, outputs int, threshold uint64) ([]uint64, error) {
+	if s.LotSize == 0 {
+		return nil, fmt.Errorf("round lot split requires a lot size greater than zero")
+	}
+
+	if total == 0 {
+		return nil, nil
+	}
+
+	lots := total / s.LotSize
+	oddLot := total % s.LotSize
+
+	// Not enough for a single lot
+	if lots == 0 {
+		return []uint64{total}, nil
+	}
+
+	outputs = clampSplitOutputs(lots, outputs)
+
+	// Reduce the number of outputs until the smallest one meets the threshold
+	for outputs > 1 && lots/uint64(outputs)*s.LotSize < threshold {
+		outputs--
+	}
+
+	amounts := make([]uint64, 0, outputs+1)
+	perOutput := lots / uint64(outputs)
+	extra := lots % uint64(outputs)
+	for i := 0; i < outputs; i++ {
+		outputLots := perOutput
+		if uint64(i) < extra {
+			outputLots++
+		}
+		amounts = append(amounts, outputLots*s.LotSize)
+	}
+
+	if oddLot > 0 {
+		amounts = append(amounts, oddLot)
+	}
+
+	return amounts, nil
+}
+
+// clampSplitOutputs keeps the number of outputs between 1 and limit so no output is empty
+func clampSplitOutputs(limit uint64, outputs int) int {
+	if outputs < 1 {
+		return 1
+	}
+	if uint64(outputs) > limit {
+		return int(limit)
+	}
+	return outputs
+}
+
+// minAmount returns the smallest of the given amounts
+func minAmount(amounts []uint64) uint64 {
+	smallest := amounts[0]
+	for _, amount := range amounts[1:] {
+		if amount < smallest {
+			smallest = amount
+		}
+	}
+	return smallest
+}
@@ -0,0 +1,236 @@
+package ordinals
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenSplitStrategies tests the amounts produced by each split strategy
+func TestTokenSplitStrategies(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  TokenSplitStrategy
+		total     uint64
+		outputs   int
+		threshold uint64
+		expected  []uint64
+		expectErr bool
+	}{
+		{
+			name:     "even split",
+			strategy: EvenSplit{},
+			total:    900,
+			outputs:  3,
+			expected: []uint64{300, 300, 300},
+		},
+		{
+			name:     "even split remainder to last output",
+			strategy: EvenSplit{},
+			total:    1000,
+			outputs:  3,
+			expected: []uint64{333, 333, 334},
+		},
+		{
+			name:      "even split below threshold reduces outputs",
+			strategy:  EvenSplit{},
+			total:     900,
+			outputs:   3,
+			threshold: 400,
+			expected:  []uint64{450, 450},
+		},
+		{
+			name:     "even split never creates empty outputs",
+			strategy: EvenSplit{},
+			total:    2,
+			outputs:  5,
+			expected: []uint64{1, 1},
+		},
+		{
+			name:     "geometric ladder",
+			strategy: GeometricSplit{Ratio: 2},
+			total:    700,
+			outputs:  3,
+			expected: []uint64{100, 200, 400},
+		},
+		{
+			name:     "geometric descending ladder",
+			strategy: GeometricSplit{Ratio: 0.5},
+			total:    700,
+			outputs:  3,
+			expected: []uint64{400, 200, 100},
+		},
+		{
+			name:     "geometric rounding to last output",
+			strategy: GeometricSplit{Ratio: 2},
+			total:    1000,
+			outputs:  3,
+			expected: []uint64{142, 285, 573},
+		},
+		{
+			name:      "geometric below threshold reduces outputs",
+			strategy:  GeometricSplit{Ratio: 2},
+			total:     700,
+			outputs:   3,
+			threshold: 150,
+			expected:  []uint64{233, 467},
+		},
+		{
+			name:     "geometric drops empty rungs",
+			strategy: GeometricSplit{Ratio: 10},
+			total:    50,
+			outputs:  3,
+			expected: []uint64{4, 46},
+		},
+		{
+			name:      "geometric invalid ratio",
+			strategy:  GeometricSplit{Ratio: 0},
+			total:     700,
+			outputs:   3,
+			expectErr: true,
+		},
+		{
+			name:     "explicit amounts",
+			strategy: ExplicitSplit{Amounts: []uint64{100, 250, 650}},
+			total:    1000,
+			expected: []uint64{100, 250, 650},
+		},
+		{
+			name:     "explicit amounts with leftover output",
+			strategy: ExplicitSplit{Amounts: []uint64{100, 250}},
+			total:    1000,
+			expected: []uint64{100, 250, 650},
+		},
+		{
+			name:      "explicit amounts exceed total",
+			strategy:  ExplicitSplit{Amounts: []uint64{600, 600}},
+			total:     1000,
+			expectErr: true,
+		},
+		{
+			name:      "explicit zero amount",
+			strategy:  ExplicitSplit{Amounts: []uint64{0}},
+			total:     1000,
+			expectErr: true,
+		},
+		{
+			name:     "round lots",
+			strategy: RoundLotSplit{LotSize: 100},
+			total:    1000,
+			outputs:  2,
+			expected: []uint64{500, 500},
+		},
+		{
+			name:     "round lots with extra lot and odd lot",
+			strategy: RoundLotSplit{LotSize: 100},
+			total:    1050,
+			outputs:  3,
+			expected: []uint64{400, 300, 300, 50},
+		},
+		{
+			name:      "round lots below threshold reduces outputs",
+			strategy:  RoundLotSplit{LotSize: 100},
+			total:     1000,
+			outputs:   4,
+			threshold: 300,
+			expected:  []uint64{400, 300, 300},
+		},
+		{
+			name:     "round lots smaller than one lot",
+			strategy: RoundLotSplit{LotSize: 100},
+			total:    50,
+			outputs:  3,
+			expected: []uint64{50},
+		},
+		{
+			name:      "round lots invalid lot size",
+			strategy:  RoundLotSplit{},
+			total:     1000,
+			outputs:   3,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts, err := tt.strategy.Split(tt.total, tt.outputs, tt.threshold)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, amounts)
+
+			// Amounts must always add up to the total
+			var sum uint64
+			for _, amount := range amounts {
+				sum += amount
+			}
+			assert.Equal(t, tt.total, sum)
+		})
+	}
+}
+
+// TestTransferOrdTokensSplitStrategy tests that a split strategy shapes the token change outputs
+func TestTransferOrdTokensSplitStrategy(t *testing.T) {
+	// Create private keys
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	cfg := &TransferBsv21TokenConfig{
+		Protocol: TokenTypeBSV21,
+		TokenID:  tokenID,
+		Utxos: []*Utxo{{
+			TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:         0,
+			ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+			Satoshis:     100000,
+		}},
+		InputTokens: []*TokenUtxo{{
+			Utxo: Utxo{
+				TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+				Vout:         0,
+				ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+				Satoshis:     1,
+			},
+			TokenID:  tokenID,
+			Protocol: TokenTypeBSV21,
+			Amount:   1100,
+		}},
+		Distributions: []*TokenDistribution{
+			{
+				Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+				Tokens:  100,
+			},
+		},
+		PaymentPk:     paymentPk,
+		OrdPk:         ordPk,
+		ChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		SplitConfig: &TokenSplitConfig{
+			Strategy: ExplicitSplit{Amounts: []uint64{250, 250}},
+		},
+	}
+
+	tx, err := TransferOrdTokens(cfg)
+	require.NoError(t, err)
+
+	// Distribution, two explicit outputs, leftover output and payment change
+	assert.Equal(t, 5, len(tx.Outputs))
+	for i := 1; i <= 3; i++ {
+		assert.Equal(t, uint64(1), tx.Outputs[i].Satoshis)
+	}
+
+	// An invalid strategy fails the transfer
+	cfg.SplitConfig = &TokenSplitConfig{
+		Strategy: ExplicitSplit{Amounts: []uint64{2000}},
+	}
+	_, err = TransferOrdTokens(cfg)
+	assert.Error(t, err)
+}
@@ -1,62 +1,59 @@
 package ordinals
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/bitcoin-sv/go-templates/template/bsv21"
 	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/bsv-blockchain/go-sdk/transaction"
-	fee_model "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
 	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
 )
 
 // DeployBsv21Token deploys a new BSV21 token
 func DeployBsv21Token(config *DeployBsv21TokenConfig) (*transaction.Transaction, error) {
+	b := newTxBuilder(false)
+	if err := buildDeployBsv21Token(b, config); err != nil {
+		return nil, err
+	}
+
+	return b.tx, nil
+}
+
+// buildDeployBsv21Token adds the inputs and outputs of DeployBsv21Token to the builder
+func buildDeployBsv21Token(b *Builder, config *DeployBsv21TokenConfig) error {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
+
 	// Validate input params
 	if config.Symbol == "" {
-		return nil, fmt.Errorf("token symbol is required")
+		return fmt.Errorf("token symbol is required")
 	}
 
 	if config.InitialDistribution == nil {
-		return nil, fmt.Errorf("initial distribution is required")
+		return fmt.Errorf("initial distribution is required")
 	}
 
 	if config.InitialDistribution.Tokens <= 0 {
-		return nil, fmt.Errorf("initial distribution amount must be greater than zero")
+		return fmt.Errorf("initial distribution amount must be greater than zero")
 	}
 
-	// Create a new transaction
-	tx := transaction.NewTransaction()
-
 	// Add inputs
-	for _, utxo := range config.Utxos {
-		unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("private key is required to sign the transaction: %w", err)
-		}
-
-		err = tx.AddInputFrom(
-			utxo.TxID,
-			utxo.Vout,
-			utxo.ScriptPubKey,
-			utxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add input: %w", err)
-		}
+	err := b.AddPaymentUtxos(config.Utxos, config.PaymentPk)
+	if err != nil {
+		return err
 	}
 
 	// Create the destination address for the token
-	dstAddr, err := script.NewAddressFromString(config.DestinationAddress)
+	dstAddr, err := parseAddress(config.DestinationAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create destination address: %w", err)
+		return fmt.Errorf("failed to create destination address: %w", err)
 	}
 
 	// Create the P2PKH script for the destination
 	p2pkhScript, err := p2pkh.Lock(dstAddr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
+		return fmt.Errorf("failed to create p2pkh script: %w", err)
 	}
 
 	// Create the BSV21 token
@@ -76,107 +73,157 @@ func DeployBsv21Token(config *DeployBsv21TokenConfig) (*transaction.Transaction,
 	// Create the token script
 	tokenScript, err := token.Lock(p2pkhScript)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create token script: %w", err)
+		return fmt.Errorf("failed to create token script: %w", err)
 	}
 
 	// Add the token output to the transaction
-	tx.AddOutput(&transaction.TransactionOutput{
+	b.addOutput(&transaction.TransactionOutput{
 		LockingScript: tokenScript,
 		Satoshis:      1, // 1 sat for ordinals
-	})
+	}, TxRoleToken)
 
-	// Ensure we have a change address
-	if config.ChangeAddress == "" && config.PaymentPk == nil {
-		return nil, fmt.Errorf("either changeAddress or paymentPk is required")
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return fmt.Errorf("failed to add change output: %w", err)
 	}
 
-	// Add change output if needed
-	if config.ChangeAddress != "" {
-		changeAddr, err := script.NewAddressFromString(config.ChangeAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change address: %w", err)
-		}
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
 
-		changeScript, err := p2pkh.Lock(changeAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change script: %w", err)
+	err = b.Fee(feeModel)
+	if err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return fmt.Errorf("not enough funds to deploy token: %w", err)
 		}
+		return fmt.Errorf("failed to calculate fee: %w", err)
+	}
 
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: changeScript,
-			Change:        true,
-		})
+	// Sign the transaction
+	_, err = b.Sign()
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	// Calculate total inputs for funds checking
-	totalIn := uint64(0)
-	for _, utxo := range config.Utxos {
-		totalIn += utxo.Satoshis
+	return nil
+}
+
+// TokenTransferResult represents the result of a token transfer
+type TokenTransferResult struct {
+	// Tx is the signed transfer transaction
+	Tx *transaction.Transaction
+	// SpentTokens are the token UTXOs spent by the transaction
+	SpentTokens []*TokenUtxo
+	// UnspentTokens are the token UTXOs from InputTokens left untouched
+	UnspentTokens []*TokenUtxo
+}
+
+// TransferOrdTokens transfers BSV21 tokens
+// This function is renamed to match the TypeScript version (transferOrdTokens)
+func TransferOrdTokens(config *TransferBsv21TokenConfig) (*transaction.Transaction, error) {
+	result, err := TransferOrdTokensWithResult(config)
+	if err != nil {
+		return nil, err
 	}
 
-	// Set fee rate using SatsPerKb if provided, otherwise use the default value
-	feeRate := config.SatsPerKb
-	if feeRate == 0 {
-		feeRate = DEFAULT_SAT_PER_KB
+	return result.Tx, nil
+}
+
+// TransferOrdTokensWithResult transfers BSV21 tokens and reports which token inputs were spent
+// In TokenInputModeNeeded only the token UTXOs chosen by SelectTokenUtxos are spent,
+// the rest are returned as UnspentTokens
+func TransferOrdTokensWithResult(config *TransferBsv21TokenConfig) (*TokenTransferResult, error) {
+	b := newTxBuilder(false)
+	spent, unspent, err := buildTransferOrdTokens(b, config)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create fee model for computation
-	feeModel := &fee_model.SatoshisPerKilobyte{
-		Satoshis: feeRate,
+	return &TokenTransferResult{
+		Tx:            b.tx,
+		SpentTokens:   spent,
+		UnspentTokens: unspent,
+	}, nil
+}
+
+// buildTransferOrdTokens adds the inputs and outputs of TransferOrdTokens to the builder
+// It returns the token inputs that were spent and the ones left untouched
+func buildTransferOrdTokens(b *Builder, config *TransferBsv21TokenConfig) ([]*TokenUtxo, []*TokenUtxo, error) {
+	// Apply the build options first, payment inputs are screened with them
+	b.options = config.BuildOptions
+
+	// Add the token inputs and outputs
+	inputTokens, unspentTokens, err := addTokenTransfer(b, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Add payment inputs
+	err = b.AddPaymentUtxos(config.Utxos, config.PaymentPk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Add change output, derived from PaymentPk if no ChangeAddress is set
+	err = b.Change(config.ChangeAddress, config.PaymentPk)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to add change output: %w", err)
 	}
 
-	err = tx.Fee(feeModel, transaction.ChangeDistributionEqual)
+	// Use the configured fee model, or SatsPerKb if none is set
+	feeModel := config.feeModel(config.SatsPerKb)
+
+	err = b.Fee(feeModel)
 	if err != nil {
-		if err.Error() == "insufficient funds for fee" {
-			return nil, fmt.Errorf("not enough funds to deploy token. Total sats in: %d", totalIn)
+		if errors.Is(err, ErrInsufficientFunds) {
+			return nil, nil, fmt.Errorf("not enough funds to transfer tokens: %w", err)
 		}
-		return nil, fmt.Errorf("failed to calculate fee: %w", err)
+		return nil, nil, fmt.Errorf("failed to calculate fee: %w", err)
 	}
 
 	// Sign the transaction
-	err = tx.Sign()
+	_, err = b.Sign()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		return nil, nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	return tx, nil
+	return inputTokens, unspentTokens, nil
 }
 
-// TransferOrdTokens transfers BSV21 tokens
-// This function is renamed to match the TypeScript version (transferOrdTokens)
-func TransferOrdTokens(config *TransferBsv21TokenConfig) (*transaction.Transaction, error) {
+// addTokenTransfer adds the token inputs, distributions, burn and token change outputs of a transfer
+// It returns the token inputs that were spent and the ones left untouched
+func addTokenTransfer(b *Builder, config *TransferBsv21TokenConfig) ([]*TokenUtxo, []*TokenUtxo, error) {
 	// Check protocol type
 	if config.Protocol != TokenTypeBSV21 {
-		return nil, fmt.Errorf("invalid protocol: expected %s, got %s", TokenTypeBSV21, config.Protocol)
+		return nil, nil, fmt.Errorf("%w: expected %s, got %s", ErrUnsupportedProtocol, TokenTypeBSV21, config.Protocol)
 	}
 
 	// Ensure input tokens match the expected tokenID
 	for _, token := range config.InputTokens {
 		if token.TokenID != config.TokenID {
-			return nil, fmt.Errorf("input tokens do not match the provided tokenID")
+			return nil, nil, fmt.Errorf("%w: input token %s doesn't match %s", ErrTokenIDMismatch, token.TokenID, config.TokenID)
 		}
 	}
 
-	// Create a new transaction
-	tx := transaction.NewTransaction()
+	// Choose which token inputs to spend
+	inputTokens, unspentTokens := selectTransferInputs(config)
 
 	// Add token inputs
 	var totalTokens uint64
-	for _, tokenUtxo := range config.InputTokens {
-		unlocker, err := p2pkh.Unlock(config.OrdPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("private key required for token input: %w", err)
+	for _, tokenUtxo := range inputTokens {
+		// Without OrdPk the token inputs are left unsigned for an external signer
+		var unlocker transaction.UnlockingScriptTemplate = &unsignedP2PKH{}
+		if config.OrdPk != nil {
+			var err error
+			unlocker, err = b.unlocker(config.OrdPk)
+			if err != nil {
+				return nil, nil, fmt.Errorf("private key required for token input: %w", err)
+			}
 		}
 
-		err = tx.AddInputFrom(
-			tokenUtxo.TxID,
-			tokenUtxo.Vout,
-			tokenUtxo.ScriptPubKey,
-			tokenUtxo.Satoshis,
-			unlocker,
-		)
+		err := b.addInput(&tokenUtxo.Utxo, unlocker, TxRoleToken)
 		if err != nil {
-			return nil, fmt.Errorf("failed to add token input: %w", err)
+			return nil, nil, fmt.Errorf("failed to add token input: %w", err)
 		}
 
 		totalTokens += tokenUtxo.Amount
@@ -190,15 +237,15 @@ func TransferOrdTokens(config *TransferBsv21TokenConfig) (*transaction.Transacti
 		distributedTokens += tokenAmount
 
 		// Create destination address
-		dstAddr, err := script.NewAddressFromString(dist.Address)
+		dstAddr, err := parseAddress(dist.Address)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create destination address: %w", err)
+			return nil, nil, fmt.Errorf("failed to create destination address: %w", err)
 		}
 
 		// Create P2PKH script
 		p2pkhScript, err := p2pkh.Lock(dstAddr)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
+			return nil, nil, fmt.Errorf("failed to create p2pkh script: %w", err)
 		}
 
 		var lockingScript *script.Script
@@ -218,173 +265,144 @@ func TransferOrdTokens(config *TransferBsv21TokenConfig) (*transaction.Transacti
 			var err error
 			lockingScript, err = token.Lock(p2pkhScript)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create token transfer script: %w", err)
+				return nil, nil, fmt.Errorf("failed to create token transfer script: %w", err)
 			}
 		}
 
 		// Add the token output to the transaction
-		tx.AddOutput(&transaction.TransactionOutput{
+		b.addOutput(&transaction.TransactionOutput{
 			LockingScript: lockingScript,
 			Satoshis:      1, // 1 sat for ordinals
-		})
+		}, TxRoleToken)
 	}
 
 	// Check if we have enough tokens
-	if distributedTokens > totalTokens {
-		return nil, fmt.Errorf("not enough tokens to satisfy the transfer amount")
+	burnTokens := config.BurnTokens
+	if distributedTokens+burnTokens > totalTokens {
+		return nil, nil, fmt.Errorf("not enough tokens to satisfy the transfer amount: %w", &InsufficientTokensError{
+			Needed:    distributedTokens + burnTokens,
+			Available: totalTokens,
+		})
 	}
 
-	// Handle remaining tokens if not burning
-	remainingTokens := totalTokens - distributedTokens
-	if remainingTokens > 0 && !config.Burn {
-		// Ensure we have a change address
-		if config.ChangeAddress == "" && config.PaymentPk == nil {
-			return nil, fmt.Errorf("ordPk or changeAddress required for token change")
+	// Remaining tokens are burned explicitly when requested rather than left unaccounted for
+	remainingTokens := totalTokens - distributedTokens - burnTokens
+	if config.BurnRemaining {
+		burnTokens += remainingTokens
+		remainingTokens = 0
+	}
+
+	if burnTokens > 0 {
+		err := createTokenBurnOutputs(b, config, burnTokens)
+		if err != nil {
+			return nil, nil, err
 		}
+	}
 
+	// Handle remaining tokens, Burn drops them without a change output
+	if remainingTokens > 0 && !config.Burn {
 		// Handle token change outputs based on input mode and split config
 		switch config.TokenInputMode {
 		case TokenInputModeAll, "":
 			// If in "all" mode or not specified, we must handle all change
-			if config.SplitConfig != nil && config.SplitConfig.Outputs > 1 {
-				err := createSplitTokenOutputs(tx, config, remainingTokens)
+			if config.SplitConfig != nil && (config.SplitConfig.Outputs > 1 || config.SplitConfig.Strategy != nil) {
+				err := createSplitTokenOutputs(b, config, remainingTokens)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			} else {
-				err := createSingleTokenChangeOutput(tx, config, remainingTokens)
+				err := createSingleTokenChangeOutput(b, config, remainingTokens)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			}
 		case TokenInputModeNeeded:
 			// In "needed" mode, we only use what's required, so add a single change output
-			err := createSingleTokenChangeOutput(tx, config, remainingTokens)
+			err := createSingleTokenChangeOutput(b, config, remainingTokens)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 	}
 
-	// Add payment inputs
-	for _, utxo := range config.Utxos {
-		unlocker, err := p2pkh.Unlock(config.PaymentPk, nil)
-		if err != nil {
-			return nil, fmt.Errorf("private key required for payment utxo: %w", err)
-		}
-
-		err = tx.AddInputFrom(
-			utxo.TxID,
-			utxo.Vout,
-			utxo.ScriptPubKey,
-			utxo.Satoshis,
-			unlocker,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add payment input: %w", err)
-		}
-	}
-
-	// Add change output if needed
-	if config.ChangeAddress != "" {
-		changeAddr, err := script.NewAddressFromString(config.ChangeAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change address: %w", err)
-		}
-
-		changeScript, err := p2pkh.Lock(changeAddr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create change script: %w", err)
-		}
+	return inputTokens, unspentTokens, nil
+}
 
-		tx.AddOutput(&transaction.TransactionOutput{
-			LockingScript: changeScript,
-			Change:        true,
-		})
+// selectTransferInputs returns the token inputs to spend and the ones to leave untouched
+// In TokenInputModeNeeded inputs are selected to cover the distributions and burn amount
+func selectTransferInputs(config *TransferBsv21TokenConfig) ([]*TokenUtxo, []*TokenUtxo) {
+	if config.TokenInputMode != TokenInputModeNeeded {
+		return config.InputTokens, nil
 	}
 
-	// Calculate total inputs for funds checking
-	totalIn := uint64(0)
-	for _, utxo := range config.Utxos {
-		totalIn += utxo.Satoshis
+	// Total raw amount the transfer needs
+	required := config.BurnTokens
+	for _, dist := range config.Distributions {
+		required += uint64(dist.Tokens)
 	}
 
-	// Set fee rate using SatsPerKb if provided, otherwise use the default value
-	feeRate := config.SatsPerKb
-	if feeRate == 0 {
-		feeRate = DEFAULT_SAT_PER_KB
-	}
+	selection := SelectTokenUtxos(
+		config.InputTokens,
+		ToToken(required, config.Decimals),
+		config.Decimals,
+		config.SelectionOptions,
+	)
 
-	// Create fee model for computation
-	feeModel := &fee_model.SatoshisPerKilobyte{
-		Satoshis: feeRate,
+	selected := make(map[*TokenUtxo]bool, len(selection.SelectedUtxos))
+	for _, tokenUtxo := range selection.SelectedUtxos {
+		selected[tokenUtxo] = true
 	}
 
-	err := tx.Fee(feeModel, transaction.ChangeDistributionEqual)
-	if err != nil {
-		if err.Error() == "insufficient funds for fee" {
-			return nil, fmt.Errorf("not enough funds to transfer tokens. Total sats in: %d", totalIn)
+	var unspent []*TokenUtxo
+	for _, tokenUtxo := range config.InputTokens {
+		if !selected[tokenUtxo] {
+			unspent = append(unspent, tokenUtxo)
 		}
-		return nil, fmt.Errorf("failed to calculate fee: %w", err)
 	}
 
-	// Sign the transaction
-	err = tx.Sign()
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
-	}
-
-	return tx, nil
+	return selection.SelectedUtxos, unspent
 }
 
 // createSplitTokenOutputs splits token change into multiple outputs according to config
 func createSplitTokenOutputs(
-	tx *transaction.Transaction,
+	b *Builder,
 	config *TransferBsv21TokenConfig,
 	remainingTokens uint64,
 ) error {
-	outputs := config.SplitConfig.Outputs
-
 	// Default threshold is 0 if not specified
 	var threshold uint64 = 0
 	if config.SplitConfig.Threshold != nil {
 		threshold = uint64(*config.SplitConfig.Threshold)
 	}
 
-	// Calculate tokens per split
-	tokensPerSplit := remainingTokens / uint64(outputs)
-
-	// If tokens per split is below threshold, reduce the number of outputs
-	// This ensures each output has at least the threshold amount of tokens
-	if tokensPerSplit < threshold && threshold > 0 {
-		outputs = int(remainingTokens / threshold)
-		if outputs == 0 {
-			outputs = 1 // Ensure at least one output
-		}
-		tokensPerSplit = remainingTokens / uint64(outputs)
+	// Default to an even split
+	strategy := config.SplitConfig.Strategy
+	if strategy == nil {
+		strategy = EvenSplit{}
 	}
 
-	// Get address for token change (use OrdPk to derive address)
-	dstAddr, err := script.NewAddressFromPublicKey(config.OrdPk.PubKey(), true)
+	// Calculate the amount of each output
+	amounts, err := strategy.Split(remainingTokens, config.SplitConfig.Outputs, threshold)
 	if err != nil {
-		return fmt.Errorf("failed to create token change address: %w", err)
+		return fmt.Errorf("failed to split token change: %w", err)
 	}
 
-	// Create P2PKH script
-	p2pkhScript, err := p2pkh.Lock(dstAddr)
-	if err != nil {
-		return fmt.Errorf("failed to create p2pkh script: %w", err)
+	var splitTotal uint64
+	for _, amount := range amounts {
+		splitTotal += amount
+	}
+	if splitTotal != remainingTokens {
+		return fmt.Errorf("token split amounts total %d, expected %d", splitTotal, remainingTokens)
 	}
 
-	// Distribute tokens across outputs
-	tokensLeft := remainingTokens
-	for i := 0; i < outputs && tokensLeft > 0; i++ {
-		// Last output gets any remainder
-		outputAmount := tokensPerSplit
-		if i == outputs-1 || tokensLeft < tokensPerSplit {
-			outputAmount = tokensLeft
-		}
+	// Get the script for token change
+	p2pkhScript, err := tokenChangeScript(b, config)
+	if err != nil {
+		return err
+	}
 
+	// Create an output for each amount
+	for _, outputAmount := range amounts {
 		var lockingScript *script.Script
 
 		if config.SplitConfig.OmitMetadata {
@@ -407,12 +425,10 @@ func createSplitTokenOutputs(
 		}
 
 		// Add token change output
-		tx.AddOutput(&transaction.TransactionOutput{
+		b.addOutput(&transaction.TransactionOutput{
 			LockingScript: lockingScript,
 			Satoshis:      1, // 1 sat for ordinals
-		})
-
-		tokensLeft -= outputAmount
+		}, TxRoleTokenChange)
 	}
 
 	return nil
@@ -420,20 +436,14 @@ func createSplitTokenOutputs(
 
 // createSingleTokenChangeOutput creates a single token change output
 func createSingleTokenChangeOutput(
-	tx *transaction.Transaction,
+	b *Builder,
 	config *TransferBsv21TokenConfig,
 	remainingTokens uint64,
 ) error {
-	// Get address for token change (use OrdPk to derive address)
-	dstAddr, err := script.NewAddressFromPublicKey(config.OrdPk.PubKey(), true)
-	if err != nil {
-		return fmt.Errorf("failed to create token change address: %w", err)
-	}
-
-	// Create P2PKH script
-	p2pkhScript, err := p2pkh.Lock(dstAddr)
+	// Get the script for token change
+	p2pkhScript, err := tokenChangeScript(b, config)
 	if err != nil {
-		return fmt.Errorf("failed to create p2pkh script: %w", err)
+		return err
 	}
 
 	var lockingScript *script.Script
@@ -461,10 +471,103 @@ func createSingleTokenChangeOutput(
 	}
 
 	// Add token change output
-	tx.AddOutput(&transaction.TransactionOutput{
+	b.addOutput(&transaction.TransactionOutput{
 		LockingScript: lockingScript,
 		Satoshis:      1, // 1 sat for ordinals
-	})
+	}, TxRoleTokenChange)
 
 	return nil
 }
+
+// createTokenBurnOutputs creates an output recording an explicit burn of tokens,
+// followed by an OP_RETURN output with the burn metadata if provided
+func createTokenBurnOutputs(
+	b *Builder,
+	config *TransferBsv21TokenConfig,
+	burnTokens uint64,
+) error {
+	// The burn output is locked to the token change address so the satoshi is not lost
+	p2pkhScript, err := tokenChangeScript(b, config)
+	if err != nil {
+		return err
+	}
+
+	// Create the token burn operation
+	token := &bsv21.Bsv21{
+		Op:  string(bsv21.OpBurn),
+		Id:  config.TokenID,
+		Amt: burnTokens,
+	}
+
+	lockingScript, err := token.Lock(p2pkhScript)
+	if err != nil {
+		return fmt.Errorf("failed to create token burn script: %w", err)
+	}
+
+	b.addOutput(&transaction.TransactionOutput{
+		LockingScript: lockingScript,
+		Satoshis:      1, // 1 sat for ordinals
+	}, TxRoleTokenBurn)
+
+	// Record the reason for the burn if provided
+	if len(config.BurnMetadata) > 0 {
+		opReturnScript, err := createMapOpReturnScript(config.BurnMetadata)
+		if err != nil {
+			return err
+		}
+
+		b.addOutput(&transaction.TransactionOutput{
+			LockingScript: opReturnScript,
+			Satoshis:      0, // 0 sats for OP_RETURN
+		}, TxRoleOpReturn)
+	}
+
+	return nil
+}
+
+// tokenChangeScript returns the P2PKH script token change is sent to
+// TokenChangeAddress is used when set, otherwise the address is derived from OrdPk
+func tokenChangeScript(b *Builder, config *TransferBsv21TokenConfig) (*script.Script, error) {
+	var dstAddr *script.Address
+	switch {
+	case config.TokenChangeAddress != "":
+		addr, err := parseAddress(config.TokenChangeAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create token change address: %w", err)
+		}
+		dstAddr = addr
+	case config.OrdPk != nil:
+		addr, err := script.NewAddressFromPublicKey(config.OrdPk.PubKey(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create token change address: %w", err)
+		}
+		dstAddr = addr
+	case b.dryRun:
+		// The address is only needed for its size in a dry run
+		return placeholderScript(), nil
+	default:
+		return nil, fmt.Errorf("tokenChangeAddress or ordPk required for token change")
+	}
+
+	// Create P2PKH script
+	p2pkhScript, err := p2pkh.Lock(dstAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create p2pkh script: %w", err)
+	}
+
+	return p2pkhScript, nil
+}
+
+// unsignedP2PKH is an unlocking template for P2PKH inputs signed outside this library
+// It reserves space for a signature when estimating fees and leaves the input unsigned
+type unsignedP2PKH struct{}
+
+// Sign leaves the unlocking script empty
+func (u *unsignedP2PKH) Sign(_ *transaction.Transaction, _ uint32) (*script.Script, error) {
+	return nil, nil
+}
+
+// EstimateLength returns the size of a P2PKH signature and compressed public key
+func (u *unsignedP2PKH) EstimateLength(_ *transaction.Transaction, _ uint32) uint32 {
+	return P2PKH_UNLOCKING_SCRIPT_SIZE
+}
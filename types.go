@@ -73,6 +73,8 @@ type TokenSplitConfig struct {
 	Threshold *float64
 	// OmitMetadata determines whether to omit metadata from token change outputs
 	OmitMetadata bool
+	// Strategy determines how token change is divided, defaults to EvenSplit
+	Strategy TokenSplitStrategy
 }
 
 // NftUtxo represents an NFT utxo
@@ -106,6 +108,8 @@ type CreateOrdinalsConfig struct {
 	PaymentPk     *ec.PrivateKey
 	ChangeAddress string
 	SatsPerKb     uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 	// AdditionalPayments is an optional list of additional payments to make
 	AdditionalPayments []*PayToAddress
 }
@@ -119,6 +123,8 @@ type SendOrdinalsConfig struct {
 	Destinations  []*Destination
 	ChangeAddress string
 	SatsPerKb     uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 	// AdditionalPayments is an optional list of additional payments to make
 	AdditionalPayments []*PayToAddress
 	// EnforceUniformSend ensures that the number of destinations matches the number of ordinals
@@ -132,6 +138,8 @@ type SendUtxosConfig struct {
 	Payments      []*PayToAddress
 	ChangeAddress string
 	SatsPerKb     uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // DeployBsv21TokenConfig represents configuration for deploying a BSV21 token
@@ -144,6 +152,8 @@ type DeployBsv21TokenConfig struct {
 	DestinationAddress  string
 	ChangeAddress       string
 	SatsPerKb           uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // TransferBsv21TokenConfig represents configuration for transferring BSV21 tokens
@@ -155,13 +165,26 @@ type TransferBsv21TokenConfig struct {
 	Distributions []*TokenDistribution
 	PaymentPk     *ec.PrivateKey
 	OrdPk         *ec.PrivateKey
+	// Burn drops any remaining tokens instead of returning them as change, no output records them
 	Burn          bool
 	ChangeAddress string
 	SatsPerKb     uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
+	// BurnTokens is a raw amount of tokens to burn with an explicit burn operation
+	BurnTokens uint64
+	// BurnRemaining burns any remaining tokens with an explicit burn operation instead of returning them as change
+	BurnRemaining bool
+	// BurnMetadata is optional MAP protocol metadata stating the reason for a burn
+	BurnMetadata map[string][]byte
 	// TokenInputMode determines how token inputs are consumed (all or only what's needed)
 	TokenInputMode TokenInputMode
+	// SelectionOptions determines how token inputs are chosen in TokenInputModeNeeded
+	SelectionOptions *TokenSelectionOptions
 	// SplitConfig configures how token change outputs are split
 	SplitConfig *TokenSplitConfig
+	// TokenChangeAddress is the address to send token change to, defaults to the OrdPk address
+	TokenChangeAddress string
 	// Decimals is the number of decimal places for the token
 	Decimals uint8
 }
@@ -179,6 +202,8 @@ type CreateOrdListingsConfig struct {
 	OrdPk         *ec.PrivateKey
 	ChangeAddress string
 	SatsPerKb     uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // CreateOrdTokenListingsConfig represents configuration for creating token listings
@@ -204,6 +229,8 @@ type CreateOrdTokenListingsConfig struct {
 	ChangeAddress string
 	// SatsPerKb is the fee rate in satoshis per kilobyte
 	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // PurchaseOrdListingConfig represents configuration for purchasing an ordinal listing
@@ -214,6 +241,8 @@ type PurchaseOrdListingConfig struct {
 	OrdAddress    string
 	ChangeAddress string
 	SatsPerKb     uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // PurchaseOrdTokenListingConfig represents configuration for purchasing a token listing
@@ -234,6 +263,8 @@ type PurchaseOrdTokenListingConfig struct {
 	ChangeAddress string
 	// SatsPerKb is the fee rate in satoshis per kilobyte
 	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 	// AdditionalPayments is an optional list of additional payments to make
 	AdditionalPayments []*PayToAddress
 	// Metadata is optional MAP protocol metadata to include in the transfer output
@@ -248,6 +279,8 @@ type CancelOrdListingsConfig struct {
 	PaymentPk     *ec.PrivateKey
 	ChangeAddress string
 	SatsPerKb     uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // CancelOrdTokenListingsConfig represents configuration for cancelling token listings
@@ -264,6 +297,8 @@ type CancelOrdTokenListingsConfig struct {
 	ChangeAddress string
 	// SatsPerKb is the fee rate in satoshis per kilobyte
 	SatsPerKb uint64
+	// BuildOptions configures how the transaction is built, e.g. a custom fee model
+	BuildOptions
 }
 
 // BroadcastResult represents the result of broadcasting a transaction
@@ -284,5 +319,5 @@ func CreateUnlocker(privateKey *ec.PrivateKey) (interface{}, error) {
 
 // Helper function to convert an address string to a script.Address
 func AddressFromString(addressStr string) (*script.Address, error) {
-	return script.NewAddressFromString(addressStr)
+	return parseAddress(addressStr)
 }
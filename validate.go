@@ -0,0 +1,436 @@
+package ordinals
+
+import (
+	"fmt"
+	"strings"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+)
+
+// ValidationIssue is a single problem found by Validate
+type ValidationIssue struct {
+	// Field is the path of the field with the problem, e.g. "Ordinals[0].Satoshis"
+	Field string `json:"field"`
+	// Message describes the problem
+	Message string `json:"message"`
+	// Err is the sentinel error of the problem, if there is one
+	Err error `json:"-"`
+}
+
+// ValidationError lists every problem found by Validate
+// It matches the sentinel errors of its issues with errors.Is
+type ValidationError struct {
+	// Issues are the problems found, in field order
+	Issues []*ValidationIssue
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, fmt.Sprintf("%s: %s", issue.Field, issue.Message))
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(messages, "; "))
+}
+
+// Unwrap returns ErrValidation and the sentinel errors of the issues
+func (e *ValidationError) Unwrap() []error {
+	errs := []error{ErrValidation}
+	for _, issue := range e.Issues {
+		if issue.Err != nil {
+			errs = append(errs, issue.Err)
+		}
+	}
+	return errs
+}
+
+// Validate checks a builder config and returns every problem found at once
+// It checks addresses, 1 sat ordinals, token IDs, duplicate outpoints, ordinals used as payments,
+// dust payments and missing keys
+// without building the transaction. The result is nil or a *ValidationError.
+func Validate(config interface{}) error {
+	v := &validator{outpoints: make(map[string]string)}
+
+	switch c := config.(type) {
+	case *CreateOrdinalsConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		for i, destination := range c.Destinations {
+			v.destination(fmt.Sprintf("Destinations[%d]", i), destination, true)
+		}
+		v.additionalPayments(c.AdditionalPayments)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *SendOrdinalsConfig:
+		v.payments("PaymentUtxos", c.PaymentUtxos, c.PaymentPk, c.PaymentScreen)
+		v.ordinals("Ordinals", c.Ordinals, c.OrdPk)
+		for i, destination := range c.Destinations {
+			v.destination(fmt.Sprintf("Destinations[%d]", i), destination, false)
+		}
+		v.additionalPayments(c.AdditionalPayments)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *SendUtxosConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		for i, payment := range c.Payments {
+			v.payment(fmt.Sprintf("Payments[%d]", i), payment)
+		}
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *DeployBsv21TokenConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		if c.Symbol == "" {
+			v.add("Symbol", nil, "symbol is required")
+		}
+		if c.InitialDistribution == nil {
+			v.add("InitialDistribution", nil, "initial distribution is required")
+		} else if c.InitialDistribution.Tokens <= 0 {
+			v.add("InitialDistribution.Tokens", nil, "initial distribution must be positive")
+		}
+		v.address("DestinationAddress", c.DestinationAddress)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *TransferBsv21TokenConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		// Without OrdPk the token inputs are left for an external signer, token change then needs an address
+		for i, token := range c.InputTokens {
+			v.token(fmt.Sprintf("InputTokens[%d]", i), token, c.TokenID)
+		}
+		if len(c.InputTokens) > 0 && c.OrdPk == nil && c.TokenChangeAddress == "" {
+			v.add("OrdPk", ErrMissingKey, "private key or token change address is required")
+		}
+		v.distributions("Distributions", c.Distributions)
+		v.optionalAddress("TokenChangeAddress", c.TokenChangeAddress)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *BurnOrdinalsConfig:
+		v.payments("PaymentUtxos", c.PaymentUtxos, c.PaymentPk, c.PaymentScreen)
+		v.ordinals("Ordinals", c.Ordinals, c.OrdPk)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *BurnOrdTokensConfig:
+		v.payments("PaymentUtxos", c.PaymentUtxos, c.PaymentPk, c.PaymentScreen)
+		v.tokens("InputTokens", c.InputTokens, c.TokenID, c.OrdPk)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *ConsolidateTokenUtxosConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		v.tokens("InputTokens", c.InputTokens, c.TokenID, c.OrdPk)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *AirdropConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		v.tokens("InputTokens", c.InputTokens, c.TokenID, c.OrdPk)
+		v.distributions("Recipients", c.Recipients)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *BatchInscribeConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		for i, destination := range c.Destinations {
+			v.destination(fmt.Sprintf("Destinations[%d]", i), destination, true)
+		}
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *CreateOrdListingsConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		for i, listing := range c.Listings {
+			field := fmt.Sprintf("Listings[%d]", i)
+			v.ordinal(field+".ListingUtxo", listing.ListingUtxo)
+			v.address(field+".PayAddress", listing.PayAddress)
+			v.address(field+".OrdAddress", listing.OrdAddress)
+		}
+		v.key("OrdPk", c.OrdPk, len(c.Listings) > 0)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *CreateOrdTokenListingsConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		for i, listing := range c.Listings {
+			field := fmt.Sprintf("Listings[%d]", i)
+			v.token(field+".ListingUtxo", listing.ListingUtxo, "")
+			v.address(field+".PayAddress", listing.PayAddress)
+			v.address(field+".OrdAddress", listing.OrdAddress)
+		}
+		v.key("OrdPk", c.OrdPk, len(c.Listings) > 0)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *PurchaseOrdListingConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		v.ordinal("ListingUtxo", c.ListingUtxo)
+		if c.ListingUtxo != nil {
+			v.listing("ListingUtxo", &c.ListingUtxo.Utxo)
+		}
+		v.address("OrdAddress", c.OrdAddress)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *PurchaseOrdTokenListingConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		v.token("ListingUtxo", c.ListingUtxo, c.TokenID)
+		if c.ListingUtxo != nil {
+			v.listing("ListingUtxo", &c.ListingUtxo.Utxo)
+		}
+		v.address("OrdAddress", c.OrdAddress)
+		v.additionalPayments(c.AdditionalPayments)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *CancelOrdListingsConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		v.ordinals("ListingUtxos", c.ListingUtxos, c.OrdPk)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *CancelOrdTokenListingsConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		v.tokens("ListingUtxos", c.ListingUtxos, "", c.OrdPk)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	case *OperationsConfig:
+		v.payments("Utxos", c.Utxos, c.PaymentPk, c.PaymentScreen)
+		v.operations(c)
+		v.change(c.ChangeAddress, c.PaymentPk, c.BuildOptions)
+	default:
+		return fmt.Errorf("%w: unsupported config type %T", ErrValidation, config)
+	}
+
+	if len(v.issues) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Issues: v.issues}
+}
+
+// validator collects the issues of a config
+type validator struct {
+	issues []*ValidationIssue
+	// outpoints maps each outpoint seen to the field it was first seen in
+	outpoints map[string]string
+}
+
+// add records an issue
+func (v *validator) add(field string, err error, format string, args ...interface{}) {
+	v.issues = append(v.issues, &ValidationIssue{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	})
+}
+
+// address checks that a required address parses
+func (v *validator) address(field, address string) {
+	if address == "" {
+		v.add(field, ErrInvalidAddress, "address is required")
+		return
+	}
+	v.optionalAddress(field, address)
+}
+
+// optionalAddress checks that an address parses if it is set
+func (v *validator) optionalAddress(field, address string) {
+	if address == "" {
+		return
+	}
+	if _, err := parseAddress(address); err != nil {
+		v.add(field, ErrInvalidAddress, "invalid address %q", address)
+	}
+}
+
+// key checks that a private key is set when it is needed
+func (v *validator) key(field string, pk *ec.PrivateKey, needed bool) {
+	if needed && pk == nil {
+		v.add(field, ErrMissingKey, "private key is required to sign")
+	}
+}
+
+// utxo checks that a UTXO is set and its outpoint isn't used twice
+func (v *validator) utxo(field string, utxo *Utxo) bool {
+	if utxo == nil {
+		v.add(field, nil, "utxo is required")
+		return false
+	}
+
+	outpoint := fmt.Sprintf("%s_%d", utxo.TxID, utxo.Vout)
+	if first, ok := v.outpoints[outpoint]; ok {
+		v.add(field, ErrDuplicateOutpoint, "outpoint %s is already used by %s", outpoint, first)
+	} else {
+		v.outpoints[outpoint] = field
+	}
+
+	return true
+}
+
+// payments checks the payment UTXOs pass the screen and the key that signs them
+func (v *validator) payments(field string, utxos []*Utxo, pk *ec.PrivateKey, screen PaymentScreen) {
+	for i, utxo := range utxos {
+		utxoField := fmt.Sprintf("%s[%d]", field, i)
+		if !v.utxo(utxoField, utxo) {
+			continue
+		}
+		if reason := screen.check(utxo); reason != "" {
+			v.add(utxoField, ErrOrdinalPayment, "payment utxo %s", reason)
+		}
+	}
+	v.key("PaymentPk", pk, len(utxos) > 0)
+}
+
+// ordinal checks an ordinal UTXO holds exactly 1 satoshi
+func (v *validator) ordinal(field string, ordinal *NftUtxo) {
+	if ordinal == nil {
+		v.add(field, nil, "ordinal is required")
+		return
+	}
+	if !v.utxo(field, &ordinal.Utxo) {
+		return
+	}
+	if ordinal.Satoshis != 1 {
+		v.add(field+".Satoshis", ErrOrdinalNotOneSat, "ordinal has %d satoshis, expected 1", ordinal.Satoshis)
+	}
+}
+
+// listing checks that a UTXO being purchased is an Ordinal Lock, its price and payout are read from the script
+func (v *validator) listing(field string, utxo *Utxo) {
+	lockingScript, err := script.NewFromHex(utxo.ScriptPubKey)
+	if err != nil || decodeOrdLock(lockingScript) == nil {
+		v.add(field+".ScriptPubKey", nil, "listing is not an Ordinal Lock")
+	}
+}
+
+// ordinals checks the ordinal UTXOs and the key that signs them
+func (v *validator) ordinals(field string, ordinals []*NftUtxo, pk *ec.PrivateKey) {
+	for i, ordinal := range ordinals {
+		v.ordinal(fmt.Sprintf("%s[%d]", field, i), ordinal)
+	}
+	v.key("OrdPk", pk, len(ordinals) > 0)
+}
+
+// token checks a token UTXO belongs to tokenID, any token ID is accepted if tokenID is empty
+func (v *validator) token(field string, token *TokenUtxo, tokenID string) {
+	if token == nil {
+		v.add(field, nil, "token utxo is required")
+		return
+	}
+	if !v.utxo(field, &token.Utxo) {
+		return
+	}
+	if tokenID != "" && token.TokenID != tokenID {
+		v.add(field+".TokenID", ErrTokenIDMismatch, "token %s doesn't match %s", token.TokenID, tokenID)
+	}
+}
+
+// tokens checks the token UTXOs and the key that signs them
+func (v *validator) tokens(field string, tokens []*TokenUtxo, tokenID string, pk *ec.PrivateKey) {
+	for i, token := range tokens {
+		v.token(fmt.Sprintf("%s[%d]", field, i), token, tokenID)
+	}
+	v.key("OrdPk", pk, len(tokens) > 0)
+}
+
+// distributions checks token distributions have an address and a positive amount
+func (v *validator) distributions(field string, distributions []*TokenDistribution) {
+	for i, dist := range distributions {
+		distField := fmt.Sprintf("%s[%d]", field, i)
+		if dist == nil {
+			v.add(distField, nil, "distribution is required")
+			continue
+		}
+		v.address(distField+".Address", dist.Address)
+		if dist.Tokens <= 0 {
+			v.add(distField+".Tokens", nil, "token amount must be positive")
+		}
+	}
+}
+
+// destination checks a destination address and, if required, its inscription
+func (v *validator) destination(field string, destination *Destination, inscriptionRequired bool) {
+	if destination == nil {
+		v.add(field, nil, "destination is required")
+		return
+	}
+	v.address(field+".Address", destination.Address)
+	if inscriptionRequired && destination.Inscription == nil {
+		v.add(field+".Inscription", nil, "inscription is required")
+	}
+}
+
+// payment checks a payment address and that the amount isn't dust
+func (v *validator) payment(field string, payment *PayToAddress) {
+	if payment == nil {
+		v.add(field, nil, "payment is required")
+		return
+	}
+	v.address(field+".Address", payment.Address)
+	v.satoshis(field+".Satoshis", payment.Satoshis)
+}
+
+// satoshis checks that an output amount isn't dust
+func (v *validator) satoshis(field string, satoshis uint64) {
+	if satoshis < DUST_LIMIT {
+		v.add(field, ErrDustOutput, "%d satoshis is below the dust limit of %d", satoshis, DUST_LIMIT)
+	}
+}
+
+// additionalPayments checks the optional additional payments
+func (v *validator) additionalPayments(payments []*PayToAddress) {
+	for i, payment := range payments {
+		v.payment(fmt.Sprintf("AdditionalPayments[%d]", i), payment)
+	}
+}
+
+// change checks that change can be sent somewhere
+func (v *validator) change(changeAddress string, paymentPk *ec.PrivateKey, options BuildOptions) {
+	if changeAddress == "" && paymentPk == nil && !options.AllowNoChange {
+		v.add("ChangeAddress", ErrNoChangeAddress, "change address or payment key is required")
+		return
+	}
+	v.optionalAddress("ChangeAddress", changeAddress)
+}
+
+// operations checks each operation of an OperationsConfig
+func (v *validator) operations(config *OperationsConfig) {
+	if len(config.Operations) == 0 {
+		v.add("Operations", nil, "at least one operation is required")
+	}
+
+	needsOrdPk := false
+	purchases, keptOrdinals := 0, 0
+	for i, op := range config.Operations {
+		field := fmt.Sprintf("Operations[%d]", i)
+
+		switch op := op.(type) {
+		case *InscribeOperation:
+			v.destination(field+".Destination", op.Destination, true)
+		case *SendOrdinalOperation:
+			v.ordinal(field+".Ordinal", op.Ordinal)
+			v.destination(field+".Destination", op.Destination, false)
+			needsOrdPk = true
+			keptOrdinals++
+		case *TransferTokensOperation:
+			for j, token := range op.InputTokens {
+				v.token(fmt.Sprintf("%s.InputTokens[%d]", field, j), token, op.TokenID)
+			}
+			v.distributions(field+".Distributions", op.Distributions)
+			for j, dist := range op.Distributions {
+				if dist != nil && dist.OmitMetadata {
+					v.add(fmt.Sprintf("%s.Distributions[%d].OmitMetadata", field, j), ErrConservation, "operations can't send tokens without metadata")
+				}
+			}
+			v.optionalAddress(field+".TokenChangeAddress", op.TokenChangeAddress)
+			needsOrdPk = needsOrdPk || (len(op.InputTokens) > 0 && op.TokenChangeAddress == "")
+		case *ListOperation:
+			v.ordinal(field+".Ordinal", op.Ordinal)
+			v.address(field+".SellerAddress", op.SellerAddress)
+			v.address(field+".PayAddress", op.PayAddress)
+			needsOrdPk = true
+			keptOrdinals++
+		case *PurchaseOperation:
+			v.ordinal(field+".Listing", op.Listing)
+			if op.Listing != nil {
+				v.listing(field+".Listing", &op.Listing.Utxo)
+			}
+			v.address(field+".OrdAddress", op.OrdAddress)
+			purchases++
+		case *BurnOperation:
+			for j, ordinal := range op.Ordinals {
+				v.ordinal(fmt.Sprintf("%s.Ordinals[%d]", field, j), ordinal)
+			}
+			needsOrdPk = needsOrdPk || len(op.Ordinals) > 0
+		case *PayOperation:
+			v.address(field+".Address", op.Address)
+			v.satoshis(field+".Satoshis", op.Satoshis)
+		case nil:
+			v.add(field, nil, "operation is required")
+		default:
+			v.add(field, nil, "unsupported operation %T", op)
+		}
+	}
+
+	// The payout of a purchase is output 1, where the satoshi of another ordinal would land
+	if purchases > 1 {
+		v.add("Operations", ErrConservation, "only one listing can be purchased per transaction")
+	} else if purchases == 1 && keptOrdinals > 0 {
+		v.add("Operations", ErrConservation, "a purchase can't be combined with sending or listing ordinals")
+	}
+
+	v.key("OrdPk", config.OrdPk, needsOrdPk)
+}
@@ -0,0 +1,195 @@
+package ordinals
+
+import (
+	"errors"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidate tests reporting every problem of a config at once
+func TestValidate(t *testing.T) {
+	tokenID := "1fcf743a77ea69755bf2b8ea70530a47de9c064daf1eee09cbc6f39e434bb0fb_0"
+
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	payment := &Utxo{
+		TxID:         "0000000000000000000000000000000000000000000000000000000000000003",
+		Vout:         0,
+		ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+		Satoshis:     100000,
+	}
+
+	// fields returns the field path of each issue
+	fields := func(err error) []string {
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		result := make([]string, 0, len(validationErr.Issues))
+		for _, issue := range validationErr.Issues {
+			result = append(result, issue.Field)
+		}
+		return result
+	}
+
+	t.Run("valid config", func(t *testing.T) {
+		err := Validate(&SendOrdinalsConfig{
+			PaymentUtxos: []*Utxo{payment},
+			Ordinals: []*NftUtxo{{Utxo: Utxo{
+				TxID:     "0000000000000000000000000000000000000000000000000000000000000004",
+				Satoshis: 1,
+			}}},
+			PaymentPk:    paymentPk,
+			OrdPk:        ordPk,
+			Destinations: []*Destination{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"}},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("send ordinals", func(t *testing.T) {
+		err := Validate(&SendOrdinalsConfig{
+			PaymentUtxos: []*Utxo{payment},
+			Ordinals: []*NftUtxo{
+				{Utxo: Utxo{TxID: "0000000000000000000000000000000000000000000000000000000000000004", Satoshis: 2}},
+				{Utxo: *payment},
+			},
+			Destinations:       []*Destination{{Address: "invalid-address"}},
+			AdditionalPayments: []*PayToAddress{{Address: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA", Satoshis: 0}},
+		})
+		require.Error(t, err)
+
+		assert.Equal(t, []string{
+			"PaymentPk",
+			"Ordinals[0].Satoshis",
+			"Ordinals[1]",
+			"Ordinals[1].Satoshis",
+			"OrdPk",
+			"Destinations[0].Address",
+			"AdditionalPayments[0].Satoshis",
+			"ChangeAddress",
+		}, fields(err))
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.ErrorIs(t, err, ErrMissingKey)
+		assert.ErrorIs(t, err, ErrOrdinalNotOneSat)
+		assert.ErrorIs(t, err, ErrDuplicateOutpoint)
+		assert.ErrorIs(t, err, ErrInvalidAddress)
+		assert.ErrorIs(t, err, ErrDustOutput)
+		assert.ErrorIs(t, err, ErrNoChangeAddress)
+		assert.Contains(t, err.Error(), "Ordinals[1]: outpoint")
+	})
+
+	t.Run("listings check ordinals", func(t *testing.T) {
+		config := &CreateOrdListingsConfig{
+			Utxos:     []*Utxo{payment},
+			PaymentPk: paymentPk,
+			OrdPk:     ordPk,
+		}
+		config.Listings = append(config.Listings, &struct {
+			PayAddress  string
+			Price       uint64
+			ListingUtxo *NftUtxo
+			OrdAddress  string
+		}{
+			PayAddress:  "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE",
+			Price:       1000,
+			ListingUtxo: &NftUtxo{Utxo: Utxo{TxID: "0000000000000000000000000000000000000000000000000000000000000004", Satoshis: 5}},
+		})
+
+		err := Validate(config)
+		assert.Equal(t, []string{"Listings[0].ListingUtxo.Satoshis", "Listings[0].OrdAddress"}, fields(err))
+	})
+
+	t.Run("mismatched token IDs", func(t *testing.T) {
+		err := Validate(&TransferBsv21TokenConfig{
+			Protocol: TokenTypeBSV21,
+			TokenID:  tokenID,
+			Utxos:    []*Utxo{payment},
+			InputTokens: []*TokenUtxo{
+				{Utxo: Utxo{TxID: "0000000000000000000000000000000000000000000000000000000000000004"}, TokenID: tokenID},
+				{Utxo: Utxo{TxID: "0000000000000000000000000000000000000000000000000000000000000005"}, TokenID: "other_0"},
+			},
+			Distributions: []*TokenDistribution{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Tokens: 0}},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+		})
+		assert.Equal(t, []string{"InputTokens[1].TokenID", "Distributions[0].Tokens"}, fields(err))
+		assert.ErrorIs(t, err, ErrTokenIDMismatch)
+	})
+
+	t.Run("externally signed token inputs", func(t *testing.T) {
+		config := &TransferBsv21TokenConfig{
+			Protocol: TokenTypeBSV21,
+			TokenID:  tokenID,
+			Utxos:    []*Utxo{payment},
+			InputTokens: []*TokenUtxo{{
+				Utxo: Utxo{
+					TxID:         "0000000000000000000000000000000000000000000000000000000000000004",
+					ScriptPubKey: "76a914b437a081c28a178b9ce5e2a0e694d45d1d5e2c0388ac",
+					Satoshis:     1,
+				},
+				TokenID:  tokenID,
+				Protocol: TokenTypeBSV21,
+				Amount:   100,
+			}},
+			Distributions:      []*TokenDistribution{{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE", Tokens: 40}},
+			PaymentPk:          paymentPk,
+			TokenChangeAddress: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA",
+		}
+
+		// Without OrdPk the builder leaves the token input unsigned, Validate agrees
+		assert.NoError(t, Validate(config))
+		tx, err := TransferOrdTokens(config)
+		require.NoError(t, err)
+		assert.Nil(t, tx.Inputs[0].UnlockingScript)
+
+		// Token change then has nowhere to go
+		config.TokenChangeAddress = ""
+		err = Validate(config)
+		assert.Equal(t, []string{"OrdPk"}, fields(err))
+		assert.ErrorIs(t, err, ErrMissingKey)
+		_, err = TransferOrdTokens(config)
+		assert.Error(t, err)
+	})
+
+	t.Run("burn tokens", func(t *testing.T) {
+		config := &BurnOrdTokensConfig{
+			Protocol:     TokenTypeBSV21,
+			TokenID:      tokenID,
+			PaymentUtxos: []*Utxo{payment},
+			InputTokens: []*TokenUtxo{
+				{Utxo: Utxo{TxID: "0000000000000000000000000000000000000000000000000000000000000004", Satoshis: 1}, TokenID: tokenID},
+			},
+			PaymentPk: paymentPk,
+			OrdPk:     ordPk,
+		}
+
+		// Zero burns every input token
+		assert.NoError(t, Validate(config))
+
+		config.OrdPk = nil
+		assert.Equal(t, []string{"OrdPk"}, fields(Validate(config)))
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		err := Validate(&OperationsConfig{
+			Utxos:     []*Utxo{payment},
+			PaymentPk: paymentPk,
+			Operations: []Operation{
+				&InscribeOperation{Destination: &Destination{Address: "1GpAScbJDFvMSUfZBYdXZiBpzW8Bfa8rPE"}},
+				&BurnOperation{Ordinals: []*NftUtxo{{Utxo: Utxo{TxID: "0000000000000000000000000000000000000000000000000000000000000004", Satoshis: 1}}}},
+				&PayOperation{Address: "1DBJ3MsNKdvuqXcmFxw9SvV6GHWmC7bxSA", Satoshis: 0},
+			},
+		})
+		assert.Equal(t, []string{"Operations[0].Destination.Inscription", "Operations[2].Satoshis", "OrdPk"}, fields(err))
+	})
+
+	t.Run("unsupported config", func(t *testing.T) {
+		err := Validate(struct{}{})
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+}
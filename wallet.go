@@ -0,0 +1,404 @@
+package ordinals
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// Key prefixes used by Wallet in its KVStore
+const (
+	walletUtxoKey  = "wallet:utxo:"
+	walletNftKey   = "wallet:nft:"
+	walletTokenKey = "wallet:token:"
+	walletTxKey    = "wallet:tx:"
+	walletSpentKey = "wallet:spent:"
+)
+
+// Wallet keeps the payment, NFT and token UTXOs of a set of addresses
+// It is goroutine-safe. UTXOs handed out by Reserve aren't handed out again until the
+// reservation is released, so concurrent builds never pick the same inputs.
+// UTXOs are persisted to a KVStore, reservations only live in memory.
+type Wallet struct {
+	mu        sync.Mutex
+	store     KVStore
+	addresses map[string]bool
+	reserved  map[string]bool
+}
+
+// ReserveRequest describes the UTXOs a transaction needs
+type ReserveRequest struct {
+	// Satoshis is the amount of payment satoshis to reserve, including a margin for the fee
+	Satoshis uint64
+	// Ordinals are the outpoints ("txid_vout") of the NFT UTXOs to reserve
+	Ordinals []string
+	// TokenID is the token to reserve Tokens of, required if Tokens is set
+	TokenID string
+	// Tokens is the amount of tokens to reserve in display format
+	Tokens float64
+}
+
+// Reservation holds UTXOs for a transaction being built
+// Release it if building or broadcasting fails, Commit it once the transaction is broadcast.
+type Reservation struct {
+	// Utxos are the reserved payment UTXOs
+	Utxos []*Utxo
+	// Ordinals are the reserved NFT UTXOs
+	Ordinals []*NftUtxo
+	// Tokens are the reserved token UTXOs
+	Tokens []*TokenUtxo
+
+	wallet    *Wallet
+	outpoints []string
+	done      bool
+}
+
+// NewWallet creates a wallet owning addresses, loading the UTXOs already in store
+// Outputs to these addresses are added when a transaction is committed or ingested.
+func NewWallet(store KVStore, addresses ...string) (*Wallet, error) {
+	w := &Wallet{
+		store:     store,
+		addresses: make(map[string]bool),
+		reserved:  make(map[string]bool),
+	}
+
+	for _, address := range addresses {
+		canonical, err := canonicalAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		w.addresses[canonical] = true
+	}
+
+	return w, nil
+}
+
+// AddUtxos adds payment UTXOs to the wallet
+func (w *Wallet) AddUtxos(utxos ...*Utxo) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	batch := newKVBatch(w.store)
+	for _, utxo := range utxos {
+		if err := putUtxo(batch, walletUtxoKey, utxo.TxID, utxo.Vout, utxo); err != nil {
+			return err
+		}
+	}
+
+	return commitBatch(batch)
+}
+
+// AddNftUtxos adds NFT UTXOs to the wallet
+func (w *Wallet) AddNftUtxos(nftUtxos ...*NftUtxo) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	batch := newKVBatch(w.store)
+	for _, nftUtxo := range nftUtxos {
+		if err := putUtxo(batch, walletNftKey, nftUtxo.TxID, nftUtxo.Vout, nftUtxo); err != nil {
+			return err
+		}
+	}
+
+	return commitBatch(batch)
+}
+
+// AddTokenUtxos adds token UTXOs to the wallet
+func (w *Wallet) AddTokenUtxos(tokenUtxos ...*TokenUtxo) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	batch := newKVBatch(w.store)
+	for _, tokenUtxo := range tokenUtxos {
+		if err := putUtxo(batch, walletTokenKey, tokenUtxo.TxID, tokenUtxo.Vout, tokenUtxo); err != nil {
+			return err
+		}
+	}
+
+	return commitBatch(batch)
+}
+
+// Utxos returns the payment UTXOs that aren't reserved
+func (w *Wallet) Utxos() ([]*Utxo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.utxos()
+}
+
+// NftUtxos returns the NFT UTXOs that aren't reserved
+func (w *Wallet) NftUtxos() ([]*NftUtxo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.nftUtxos()
+}
+
+// TokenUtxos returns the token UTXOs of tokenID that aren't reserved, or of every token if it is empty
+func (w *Wallet) TokenUtxos(tokenID string) ([]*TokenUtxo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.tokenUtxos(tokenID)
+}
+
+// Reserve reserves the UTXOs a transaction needs
+// Payment UTXOs are picked largest first. It fails with an InsufficientFundsError or
+// InsufficientTokensError if the available UTXOs can't cover the request, and with
+// ErrUtxoUnavailable if an ordinal isn't in the wallet or is already reserved.
+// Reserving tokens requires a TokenID, tokens of different IDs can't be spent together.
+func (w *Wallet) Reserve(request *ReserveRequest) (*Reservation, error) {
+	if request.Tokens > 0 && request.TokenID == "" {
+		return nil, fmt.Errorf("%w: TokenID is required to reserve tokens", ErrValidation)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r := &Reservation{wallet: w}
+
+	// Pick payment UTXOs
+	if request.Satoshis > 0 {
+		utxos, err := w.utxos()
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(utxos, func(i, j int) bool {
+			return utxos[i].Satoshis > utxos[j].Satoshis
+		})
+
+		var total uint64
+		for _, utxo := range utxos {
+			if total >= request.Satoshis {
+				break
+			}
+			r.Utxos = append(r.Utxos, utxo)
+			total += utxo.Satoshis
+		}
+		if total < request.Satoshis {
+			return nil, &InsufficientFundsError{Needed: request.Satoshis, Available: total}
+		}
+	}
+
+	// Pick the requested ordinals
+	if len(request.Ordinals) > 0 {
+		nftUtxos, err := w.nftUtxos()
+		if err != nil {
+			return nil, err
+		}
+		available := make(map[string]*NftUtxo, len(nftUtxos))
+		for _, nftUtxo := range nftUtxos {
+			available[outpointKey(nftUtxo.TxID, nftUtxo.Vout)] = nftUtxo
+		}
+
+		for _, outpoint := range request.Ordinals {
+			nftUtxo, ok := available[outpoint]
+			if !ok {
+				return nil, fmt.Errorf("%w: ordinal %s", ErrUtxoUnavailable, outpoint)
+			}
+			delete(available, outpoint)
+			r.Ordinals = append(r.Ordinals, nftUtxo)
+		}
+	}
+
+	// Pick token UTXOs
+	if request.Tokens > 0 {
+		tokenUtxos, err := w.tokenUtxos(request.TokenID)
+		if err != nil {
+			return nil, err
+		}
+
+		var decimals uint8
+		if len(tokenUtxos) > 0 {
+			decimals = tokenUtxos[0].Decimals
+		}
+
+		selection := SelectTokenUtxos(tokenUtxos, request.Tokens, decimals, nil)
+		if !selection.IsEnough {
+			return nil, &InsufficientTokensError{
+				Needed:    FromToken(request.Tokens, decimals),
+				Available: FromToken(selection.TotalSelected, decimals),
+			}
+		}
+		r.Tokens = selection.SelectedUtxos
+	}
+
+	// Hold everything picked
+	for _, utxo := range r.Utxos {
+		r.outpoints = append(r.outpoints, outpointKey(utxo.TxID, utxo.Vout))
+	}
+	for _, nftUtxo := range r.Ordinals {
+		r.outpoints = append(r.outpoints, outpointKey(nftUtxo.TxID, nftUtxo.Vout))
+	}
+	for _, tokenUtxo := range r.Tokens {
+		r.outpoints = append(r.outpoints, outpointKey(tokenUtxo.TxID, tokenUtxo.Vout))
+	}
+	for _, outpoint := range r.outpoints {
+		w.reserved[outpoint] = true
+	}
+
+	return r, nil
+}
+
+// Release returns the reserved UTXOs to the wallet, releasing twice or after Commit has no effect
+func (r *Reservation) Release() {
+	w := r.wallet
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if r.done {
+		return
+	}
+	r.done = true
+
+	for _, outpoint := range r.outpoints {
+		delete(w.reserved, outpoint)
+	}
+}
+
+// Commit records a broadcast transaction and releases the reservation
+// The transaction is ingested like with Wallet.Ingest.
+func (r *Reservation) Commit(tx *transaction.Transaction) error {
+	w := r.wallet
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if r.done {
+		return fmt.Errorf("reservation is already released or committed")
+	}
+
+	if err := w.applyTransaction(tx); err != nil {
+		return err
+	}
+
+	r.done = true
+	for _, outpoint := range r.outpoints {
+		delete(w.reserved, outpoint)
+	}
+
+	return nil
+}
+
+// utxos reads the payment UTXOs that aren't reserved
+func (w *Wallet) utxos() ([]*Utxo, error) {
+	var utxos []*Utxo
+	err := w.each(walletUtxoKey, func(outpoint string, value []byte) error {
+		utxo := &Utxo{}
+		if err := json.Unmarshal(value, utxo); err != nil {
+			return err
+		}
+		utxos = append(utxos, utxo)
+		return nil
+	})
+
+	return utxos, err
+}
+
+// nftUtxos reads the NFT UTXOs that aren't reserved
+func (w *Wallet) nftUtxos() ([]*NftUtxo, error) {
+	var nftUtxos []*NftUtxo
+	err := w.each(walletNftKey, func(outpoint string, value []byte) error {
+		nftUtxo := &NftUtxo{}
+		if err := json.Unmarshal(value, nftUtxo); err != nil {
+			return err
+		}
+		nftUtxos = append(nftUtxos, nftUtxo)
+		return nil
+	})
+
+	return nftUtxos, err
+}
+
+// tokenUtxos reads the token UTXOs of tokenID that aren't reserved, or of every token if it is empty
+func (w *Wallet) tokenUtxos(tokenID string) ([]*TokenUtxo, error) {
+	var tokenUtxos []*TokenUtxo
+	err := w.each(walletTokenKey, func(outpoint string, value []byte) error {
+		tokenUtxo := &TokenUtxo{}
+		if err := json.Unmarshal(value, tokenUtxo); err != nil {
+			return err
+		}
+		if tokenID == "" || tokenUtxo.TokenID == tokenID {
+			tokenUtxos = append(tokenUtxos, tokenUtxo)
+		}
+		return nil
+	})
+
+	return tokenUtxos, err
+}
+
+// each calls fn with every stored value under prefix whose outpoint isn't reserved
+func (w *Wallet) each(prefix string, fn func(outpoint string, value []byte) error) error {
+	keys, err := w.store.Keys(prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list utxos: %w", err)
+	}
+
+	for _, key := range keys {
+		outpoint := key[len(prefix):]
+		if w.reserved[outpoint] {
+			continue
+		}
+
+		value, err := w.store.Get(key)
+		if err != nil {
+			return fmt.Errorf("failed to read utxo: %w", err)
+		}
+		if value == nil {
+			continue
+		}
+
+		if err = fn(outpoint, value); err != nil {
+			return fmt.Errorf("failed to decode utxo %s: %w", outpoint, err)
+		}
+	}
+
+	return nil
+}
+
+// putUtxo stores a UTXO as JSON under prefix and its outpoint
+func putUtxo(store KVStore, prefix string, txid string, vout uint32, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode utxo: %w", err)
+	}
+
+	if err = store.Set(prefix+outpointKey(txid, vout), data); err != nil {
+		return fmt.Errorf("failed to save utxo: %w", err)
+	}
+
+	return nil
+}
+
+// commitBatch saves the writes staged in batch
+func commitBatch(batch *kvBatch) error {
+	if err := batch.Commit(); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+
+	return nil
+}
+
+// outpointKey formats an outpoint as "txid_vout"
+func outpointKey(txid string, vout uint32) string {
+	return fmt.Sprintf("%s_%d", txid, vout)
+}
+
+// canonicalAddress returns the mainnet address string of the public key hash of address
+// Parsed outputs are compared by this form.
+func canonicalAddress(address string) (string, error) {
+	addr, err := parseAddress(address)
+	if err != nil {
+		return "", err
+	}
+
+	canonical, err := script.NewAddressFromPublicKeyHash(addr.PublicKeyHash, true)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %w", ErrInvalidAddress, address, err)
+	}
+
+	return canonical.AddressString, nil
+}
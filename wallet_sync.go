@@ -0,0 +1,176 @@
+package ordinals
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-templates/template/bsv21"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// Ingest updates the wallet from transactions it built or received
+// The UTXOs each transaction spends are removed, and its outputs to the wallet's addresses are
+// added: BSV21 transfers and mints as token UTXOs, other 1 sat outputs as NFT UTXOs and P2PKH
+// outputs as payment UTXOs. Burns and listings aren't spendable and are skipped.
+// Ingesting a transaction twice has no effect, and an output spent by an already ingested
+// transaction isn't added, so transactions can be ingested in any order.
+func (w *Wallet) Ingest(txs ...*transaction.Transaction) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, tx := range txs {
+		if err := w.applyTransaction(tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsIngested returns true if the transaction was ingested or committed
+func (w *Wallet) IsIngested(txid string) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	value, err := w.store.Get(walletTxKey + txid)
+	if err != nil {
+		return false, fmt.Errorf("failed to read transaction state: %w", err)
+	}
+
+	return value != nil, nil
+}
+
+// applyTransaction removes the UTXOs spent by tx and adds its outputs to owned addresses
+func (w *Wallet) applyTransaction(tx *transaction.Transaction) error {
+	txid := tx.TxID().String()
+	done, err := w.store.Get(walletTxKey + txid)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction state: %w", err)
+	}
+	if done != nil {
+		return nil
+	}
+
+	// Stage all changes, so the transaction is saved as one write
+	batch := newKVBatch(w.store)
+
+	// Token outputs keep the decimals of the tokens they were made from
+	decimals := make(map[string]uint8)
+
+	// Remove spent UTXOs and remember them in case their transaction is ingested later
+	for _, input := range tx.Inputs {
+		outpoint := outpointKey(input.SourceTXID.String(), input.SourceTxOutIndex)
+
+		value, err := batch.Get(walletTokenKey + outpoint)
+		if err != nil {
+			return fmt.Errorf("failed to read utxo: %w", err)
+		}
+		if value != nil {
+			tokenUtxo := &TokenUtxo{}
+			if err = json.Unmarshal(value, tokenUtxo); err != nil {
+				return fmt.Errorf("failed to decode utxo %s: %w", outpoint, err)
+			}
+			decimals[tokenUtxo.TokenID] = tokenUtxo.Decimals
+		}
+
+		for _, prefix := range []string{walletUtxoKey, walletNftKey, walletTokenKey} {
+			if err = batch.Delete(prefix + outpoint); err != nil {
+				return fmt.Errorf("failed to remove spent utxo: %w", err)
+			}
+		}
+		if err = batch.Set(walletSpentKey+outpoint, []byte{1}); err != nil {
+			return fmt.Errorf("failed to save spent utxo: %w", err)
+		}
+	}
+
+	// Add the outputs the wallet owns
+	for vout, output := range tx.Outputs {
+		parsed := ParseOutput(txid, uint32(vout), output)
+		if !w.addresses[parsed.Address] {
+			continue
+		}
+
+		spent, err := batch.Get(walletSpentKey + outpointKey(txid, uint32(vout)))
+		if err != nil {
+			return fmt.Errorf("failed to read utxo: %w", err)
+		}
+		if spent != nil {
+			continue
+		}
+
+		utxo := Utxo{
+			TxID:         txid,
+			Vout:         uint32(vout),
+			ScriptPubKey: output.LockingScript.String(),
+			Satoshis:     output.Satoshis,
+		}
+
+		switch {
+		case parsed.Kind == OutputKindOrdLock:
+			// Listings are spent by the buyer or cancelled, they aren't spendable UTXOs
+			continue
+		case parsed.Token != nil:
+			if parsed.Token.Op == string(bsv21.OpBurn) {
+				continue
+			}
+			tokenDecimals, ok := decimals[parsed.Token.ID]
+			if !ok {
+				if tokenDecimals, err = storedTokenDecimals(batch, parsed.Token.ID, parsed.Token.Decimals); err != nil {
+					return err
+				}
+			}
+			err = putUtxo(batch, walletTokenKey, txid, uint32(vout), &TokenUtxo{
+				Utxo:     utxo,
+				TokenID:  parsed.Token.ID,
+				Protocol: TokenTypeBSV21,
+				Amount:   parsed.Token.Amount,
+				Decimals: tokenDecimals,
+			})
+		case output.Satoshis == 1:
+			err = putUtxo(batch, walletNftKey, txid, uint32(vout), &NftUtxo{
+				Utxo:        utxo,
+				ContentType: parsed.ContentType,
+			})
+		case parsed.Kind == OutputKindP2PKH:
+			err = putUtxo(batch, walletUtxoKey, txid, uint32(vout), &utxo)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = batch.Set(walletTxKey+txid, []byte{1}); err != nil {
+		return fmt.Errorf("failed to save transaction state: %w", err)
+	}
+
+	return commitBatch(batch)
+}
+
+// storedTokenDecimals returns the decimals of a token the wallet already holds, or fallback if it holds none
+// Transfers don't write the decimals, only the deploy+mint does.
+func storedTokenDecimals(store KVStore, tokenID string, fallback uint8) (uint8, error) {
+	keys, err := store.Keys(walletTokenKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list utxos: %w", err)
+	}
+
+	for _, key := range keys {
+		value, err := store.Get(key)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read utxo: %w", err)
+		}
+		if value == nil {
+			continue
+		}
+
+		tokenUtxo := &TokenUtxo{}
+		if err = json.Unmarshal(value, tokenUtxo); err != nil {
+			return 0, fmt.Errorf("failed to decode utxo %s: %w", key, err)
+		}
+		if tokenUtxo.TokenID == tokenID {
+			return tokenUtxo.Decimals, nil
+		}
+	}
+
+	return fallback, nil
+}
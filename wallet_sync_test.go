@@ -0,0 +1,136 @@
+package ordinals
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWalletIngest tests building a chain of dependent transactions from the wallet alone
+func TestWalletIngest(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	payAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+	require.NoError(t, err)
+	otherPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	otherAddr, err := script.NewAddressFromPublicKey(otherPk.PubKey(), true)
+	require.NoError(t, err)
+
+	newWallet := func(t *testing.T) *Wallet {
+		wallet, err := NewWallet(NewMemoryStore(), payAddr.AddressString, ordAddr.AddressString)
+		require.NoError(t, err)
+		require.NoError(t, wallet.AddUtxos(walletUtxos(t, payAddr, 1, 100000)...))
+		return wallet
+	}
+
+	// deploy deploys a token from the wallet's payment UTXOs
+	deploy := func(t *testing.T, wallet *Wallet) *transaction.Transaction {
+		utxos, err := wallet.Utxos()
+		require.NoError(t, err)
+
+		tx, err := DeployBsv21Token(&DeployBsv21TokenConfig{
+			Symbol:              "TEST",
+			Utxos:               utxos,
+			InitialDistribution: &TokenDistribution{Address: ordAddr.AddressString, Tokens: 1000},
+			PaymentPk:           paymentPk,
+			DestinationAddress:  ordAddr.AddressString,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	// transfer sends 10 tokens to address using only the wallet's UTXOs
+	transfer := func(t *testing.T, wallet *Wallet, tokenID, address string) *transaction.Transaction {
+		utxos, err := wallet.Utxos()
+		require.NoError(t, err)
+		tokenUtxos, err := wallet.TokenUtxos(tokenID)
+		require.NoError(t, err)
+
+		tx, err := TransferOrdTokens(&TransferBsv21TokenConfig{
+			Protocol:      TokenTypeBSV21,
+			TokenID:       tokenID,
+			Utxos:         utxos,
+			InputTokens:   tokenUtxos,
+			Distributions: []*TokenDistribution{{Address: address, Tokens: 10}},
+			PaymentPk:     paymentPk,
+			OrdPk:         ordPk,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	// tokenTotal sums the wallet's raw token amounts
+	tokenTotal := func(t *testing.T, wallet *Wallet, tokenID string) uint64 {
+		tokenUtxos, err := wallet.TokenUtxos(tokenID)
+		require.NoError(t, err)
+
+		var total uint64
+		for _, tokenUtxo := range tokenUtxos {
+			total += tokenUtxo.Amount
+		}
+		return total
+	}
+
+	t.Run("chain of transfers", func(t *testing.T) {
+		wallet := newWallet(t)
+
+		deployTx := deploy(t, wallet)
+		require.NoError(t, wallet.Ingest(deployTx))
+		tokenID := deployTx.TxID().String() + "_0"
+		assert.Equal(t, uint64(1000), tokenTotal(t, wallet, tokenID))
+
+		for i := 0; i < 50; i++ {
+			tx := transfer(t, wallet, tokenID, otherAddr.AddressString)
+			require.NoError(t, wallet.Ingest(tx))
+		}
+		assert.Equal(t, uint64(500), tokenTotal(t, wallet, tokenID))
+
+		utxos, err := wallet.Utxos()
+		require.NoError(t, err)
+		require.Len(t, utxos, 1)
+		assert.Less(t, utxos[0].Satoshis, uint64(100000))
+
+		// Ingesting again has no effect
+		require.NoError(t, wallet.Ingest(deployTx))
+		assert.Equal(t, uint64(500), tokenTotal(t, wallet, tokenID))
+
+		ingested, err := wallet.IsIngested(deployTx.TxID().String())
+		require.NoError(t, err)
+		assert.True(t, ingested)
+	})
+
+	t.Run("incoming tokens and out of order ingestion", func(t *testing.T) {
+		wallet := newWallet(t)
+
+		deployTx := deploy(t, wallet)
+		tokenID := deployTx.TxID().String() + "_0"
+		require.NoError(t, wallet.Ingest(deployTx))
+		transferTx := transfer(t, wallet, tokenID, ordAddr.AddressString)
+
+		// A fresh wallet sees the transfer before the deploy, the spent deploy outputs aren't added
+		fresh, err := NewWallet(NewMemoryStore(), ordAddr.AddressString)
+		require.NoError(t, err)
+		require.NoError(t, fresh.Ingest(transferTx, deployTx))
+		assert.Equal(t, uint64(1000), tokenTotal(t, fresh, tokenID))
+
+		tokenUtxos, err := fresh.TokenUtxos(tokenID)
+		require.NoError(t, err)
+		for _, tokenUtxo := range tokenUtxos {
+			assert.Equal(t, transferTx.TxID().String(), tokenUtxo.TxID)
+		}
+
+		// The fresh wallet doesn't own the payment address
+		utxos, err := fresh.Utxos()
+		require.NoError(t, err)
+		assert.Empty(t, utxos)
+	})
+}
@@ -0,0 +1,291 @@
+package ordinals
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/bitcoin-sv/go-templates/template/inscription"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// walletUtxos creates count payment UTXOs of sats each locked to address
+func walletUtxos(t *testing.T, address *script.Address, count int, sats uint64) []*Utxo {
+	lockingScript, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+
+	utxos := make([]*Utxo, count)
+	for i := range utxos {
+		utxos[i] = &Utxo{
+			TxID:         fmt.Sprintf("%064x", i+1),
+			Vout:         0,
+			ScriptPubKey: lockingScript.String(),
+			Satoshis:     sats,
+		}
+	}
+
+	return utxos
+}
+
+// TestWalletReserve tests reserving and releasing UTXOs
+func TestWalletReserve(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	payAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	require.NoError(t, err)
+
+	newWallet := func(t *testing.T) *Wallet {
+		wallet, err := NewWallet(NewMemoryStore(), payAddr.AddressString)
+		require.NoError(t, err)
+		require.NoError(t, wallet.AddUtxos(walletUtxos(t, payAddr, 3, 1000)...))
+		require.NoError(t, wallet.AddNftUtxos(&NftUtxo{Utxo: Utxo{TxID: fmt.Sprintf("%064x", 10), Vout: 0, Satoshis: 1}}))
+		require.NoError(t, wallet.AddTokenUtxos(
+			&TokenUtxo{Utxo: Utxo{TxID: fmt.Sprintf("%064x", 11), Vout: 0, Satoshis: 1}, TokenID: "token_0", Protocol: TokenTypeBSV21, Amount: 500},
+			&TokenUtxo{Utxo: Utxo{TxID: fmt.Sprintf("%064x", 12), Vout: 0, Satoshis: 1}, TokenID: "token_0", Protocol: TokenTypeBSV21, Amount: 500},
+		))
+		return wallet
+	}
+
+	t.Run("reserved utxos are not handed out again", func(t *testing.T) {
+		wallet := newWallet(t)
+
+		reservation, err := wallet.Reserve(&ReserveRequest{
+			Satoshis: 1500,
+			Ordinals: []string{fmt.Sprintf("%064x_0", 10)},
+			TokenID:  "token_0",
+			Tokens:   600,
+		})
+		require.NoError(t, err)
+		assert.Len(t, reservation.Utxos, 2)
+		assert.Len(t, reservation.Ordinals, 1)
+		assert.Len(t, reservation.Tokens, 2)
+
+		utxos, err := wallet.Utxos()
+		require.NoError(t, err)
+		assert.Len(t, utxos, 1)
+
+		_, err = wallet.Reserve(&ReserveRequest{Satoshis: 1500})
+		var fundsErr *InsufficientFundsError
+		require.ErrorAs(t, err, &fundsErr)
+		assert.Equal(t, uint64(1000), fundsErr.Available)
+
+		_, err = wallet.Reserve(&ReserveRequest{Ordinals: []string{fmt.Sprintf("%064x_0", 10)}})
+		require.ErrorIs(t, err, ErrUtxoUnavailable)
+
+		_, err = wallet.Reserve(&ReserveRequest{TokenID: "token_0", Tokens: 1})
+		require.ErrorIs(t, err, ErrInsufficientTokens)
+
+		// Releasing makes them available again, releasing twice has no effect
+		reservation.Release()
+		reservation.Release()
+
+		utxos, err = wallet.Utxos()
+		require.NoError(t, err)
+		assert.Len(t, utxos, 3)
+
+		tokenUtxos, err := wallet.TokenUtxos("token_0")
+		require.NoError(t, err)
+		assert.Len(t, tokenUtxos, 2)
+	})
+
+	t.Run("failed reserve holds nothing", func(t *testing.T) {
+		wallet := newWallet(t)
+
+		_, err := wallet.Reserve(&ReserveRequest{Satoshis: 1000, Ordinals: []string{"unknown_0"}})
+		require.ErrorIs(t, err, ErrUtxoUnavailable)
+
+		_, err = wallet.Reserve(&ReserveRequest{Satoshis: 1000, Tokens: 1})
+		require.ErrorIs(t, err, ErrValidation)
+
+		utxos, err := wallet.Utxos()
+		require.NoError(t, err)
+		assert.Len(t, utxos, 3)
+	})
+
+	t.Run("concurrent reservations never overlap", func(t *testing.T) {
+		wallet, err := NewWallet(NewMemoryStore(), payAddr.AddressString)
+		require.NoError(t, err)
+		require.NoError(t, wallet.AddUtxos(walletUtxos(t, payAddr, 50, 1000)...))
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		seen := make(map[string]int)
+		failed := 0
+		for i := 0; i < 60; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reservation, err := wallet.Reserve(&ReserveRequest{Satoshis: 1000})
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					assert.True(t, errors.Is(err, ErrInsufficientFunds))
+					failed++
+					return
+				}
+				for _, utxo := range reservation.Utxos {
+					seen[outpointKey(utxo.TxID, utxo.Vout)]++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, seen, 50)
+		for outpoint, count := range seen {
+			assert.Equal(t, 1, count, outpoint)
+		}
+		assert.Equal(t, 10, failed)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := NewWallet(NewMemoryStore(), "invalid")
+		require.ErrorIs(t, err, ErrInvalidAddress)
+	})
+}
+
+// TestWalletCommit tests recording a broadcast transaction
+func TestWalletCommit(t *testing.T) {
+	paymentPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	payAddr, err := script.NewAddressFromPublicKey(paymentPk.PubKey(), true)
+	require.NoError(t, err)
+	ordPk, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	ordAddr, err := script.NewAddressFromPublicKey(ordPk.PubKey(), true)
+	require.NoError(t, err)
+
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "wallet.json"))
+	require.NoError(t, err)
+	wallet, err := NewWallet(store, payAddr.AddressString, ordAddr.AddressString)
+	require.NoError(t, err)
+	require.NoError(t, wallet.AddUtxos(walletUtxos(t, payAddr, 2, 10000)...))
+
+	reservation, err := wallet.Reserve(&ReserveRequest{Satoshis: 5000})
+	require.NoError(t, err)
+	require.Len(t, reservation.Utxos, 1)
+
+	tx, err := CreateOrdinals(&CreateOrdinalsConfig{
+		Utxos: reservation.Utxos,
+		Destinations: []*Destination{{
+			Address:     ordAddr.AddressString,
+			Inscription: &inscription.Inscription{File: inscription.File{Content: []byte("hello"), Type: "text/plain"}},
+		}},
+		PaymentPk: paymentPk,
+	})
+	require.NoError(t, err)
+	require.NoError(t, reservation.Commit(tx))
+
+	// Committing again or releasing after a commit has no effect
+	require.Error(t, reservation.Commit(tx))
+	reservation.Release()
+
+	txid := tx.TxID().String()
+	nftUtxos, err := wallet.NftUtxos()
+	require.NoError(t, err)
+	require.Len(t, nftUtxos, 1)
+	assert.Equal(t, txid, nftUtxos[0].TxID)
+	assert.Equal(t, "text/plain", nftUtxos[0].ContentType)
+
+	// The spent UTXO is gone and the change is added
+	utxos, err := wallet.Utxos()
+	require.NoError(t, err)
+	require.Len(t, utxos, 2)
+	outpoints := []string{outpointKey(utxos[0].TxID, utxos[0].Vout), outpointKey(utxos[1].TxID, utxos[1].Vout)}
+	assert.Contains(t, outpoints, outpointKey(txid, 1))
+	assert.NotContains(t, outpoints, outpointKey(reservation.Utxos[0].TxID, reservation.Utxos[0].Vout))
+
+	// The state survives reopening the file store
+	reopened, err := NewFileStore(store.path)
+	require.NoError(t, err)
+	wallet, err = NewWallet(reopened, payAddr.AddressString, ordAddr.AddressString)
+	require.NoError(t, err)
+
+	reopenedUtxos, err := wallet.Utxos()
+	require.NoError(t, err)
+	assert.Equal(t, utxos, reopenedUtxos)
+}
+
+// TestKVStoreApply tests applying several writes as one change
+func TestKVStoreApply(t *testing.T) {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	require.NoError(t, err)
+
+	for name, store := range map[string]interface {
+		KVStore
+		KVBatcher
+	}{"memory": NewMemoryStore(), "file": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.Set("a", []byte("1")))
+
+			// Later writes of a key win, nil deletes it
+			require.NoError(t, store.Apply([]KVWrite{
+				{Key: "a"},
+				{Key: "b", Value: []byte("2")},
+				{Key: "b", Value: []byte("3")},
+			}))
+
+			value, err := store.Get("a")
+			require.NoError(t, err)
+			assert.Nil(t, value)
+			value, err = store.Get("b")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("3"), value)
+		})
+	}
+
+	t.Run("file store keeps nothing when saving fails", func(t *testing.T) {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "missing", "store.json"))
+		require.NoError(t, err)
+
+		require.Error(t, store.Apply([]KVWrite{{Key: "a", Value: []byte("1")}}))
+		keys, err := store.Keys("")
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		store := NewMemoryStore()
+		require.NoError(t, store.Set("key:a", []byte("1")))
+
+		batch := newKVBatch(store)
+		require.NoError(t, batch.Delete("key:a"))
+		require.NoError(t, batch.Set("key:b", []byte("2")))
+
+		// The batch reads its own writes, the store is unchanged until Commit
+		keys, err := batch.Keys("key:")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"key:b"}, keys)
+		keys, err = store.Keys("key:")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"key:a"}, keys)
+
+		require.NoError(t, batch.Commit())
+		keys, err = store.Keys("key:")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"key:b"}, keys)
+	})
+
+	t.Run("batch on a store without Apply", func(t *testing.T) {
+		// Embedding the interface hides MemoryStore.Apply
+		store := struct{ KVStore }{NewMemoryStore()}
+		_, ok := KVStore(store).(KVBatcher)
+		require.False(t, ok)
+		require.NoError(t, store.Set("key:a", []byte("1")))
+
+		batch := newKVBatch(store)
+		require.NoError(t, batch.Delete("key:a"))
+		require.NoError(t, batch.Set("key:b", []byte("2")))
+		require.NoError(t, batch.Commit())
+
+		keys, err := store.Keys("key:")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"key:b"}, keys)
+	})
+}